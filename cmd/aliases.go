@@ -0,0 +1,46 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+
+// aliases returns the built-in nicknames merged with the ones from the
+// aliases config entry.
+func aliases() namnsdag.Aliases {
+	if cfg.NoDefaultAliases {
+		return cfg.Aliases
+	}
+	return namnsdag.DefaultAliases.Merge(cfg.Aliases)
+}
+
+// findName returns all occurrences of a given name. If the name is not
+// found, but is a known alias such as "Lasse", then the occurrences of the
+// name it is short for are returned instead.
+func findName(cache namnsdag.Cache, name string) []namnsdag.Name {
+	names := cache.FindName(name)
+	if len(names) > 0 {
+		return names
+	}
+	if resolved, ok := aliases().Resolve(name); ok {
+		return cache.FindName(resolved)
+	}
+	return nil
+}
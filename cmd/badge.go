@@ -0,0 +1,154 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	badgeFormatSVG = "svg"
+	badgeFormatPNG = "png"
+
+	badgeLabel        = "namnsdag"
+	badgeLabelColor   = "#555"
+	badgeMessageColor = "#007ec6"
+)
+
+var badgeFlags = struct {
+	format string
+}{}
+
+var badgeCmd = &cobra.Command{
+	Use:   "badge [YYYY-MM-DD]",
+	Short: "Generate a badge image with today's names",
+	Long: `Generate a badge image with today's names, in the style of shields.io,
+to embed in a README or dashboard. The image is written to stdout.`,
+	Example: `  namnsdag badge --format svg > namnsdag.svg`,
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		day := now()
+		if len(args) == 1 {
+			var err error
+			day, err = parseDate(args[0])
+			if err != nil {
+				return fmt.Errorf("parse argument: %w", err)
+			}
+		}
+		cache, err := loadOrFetchNames(day)
+		if err != nil {
+			return err
+		}
+		message := badgeMessage(namesForToday(cache, day))
+		switch badgeFlags.format {
+		case badgeFormatSVG:
+			_, err := io.WriteString(stdout, renderBadgeSVG(badgeLabel, message))
+			return err
+		case badgeFormatPNG:
+			return renderBadgePNG(stdout, badgeLabel, message)
+		default:
+			return fmt.Errorf("unknown badge format %q, must be one of: svg, png", badgeFlags.format)
+		}
+	},
+}
+
+func badgeMessage(names []namnsdag.Name) string {
+	if len(names) == 0 {
+		return "no names"
+	}
+	var titles []string
+	for _, name := range names {
+		titles = append(titles, name.Name)
+	}
+	return strings.Join(titles, ", ")
+}
+
+// badgeTextWidth estimates the width in pixels of a text in the badge's
+// 11px Verdana font.
+func badgeTextWidth(text string) int {
+	return utf8.RuneCountInString(text) * 7
+}
+
+// renderBadgeSVG renders a badge in the style of the "flat" shields.io
+// badges.
+func renderBadgeSVG(label, message string) string {
+	labelWidth := badgeTextWidth(label) + 10
+	messageWidth := badgeTextWidth(message) + 10
+	width := labelWidth + messageWidth
+	label, message = html.EscapeString(label), html.EscapeString(message)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`+"\n", width, label, message)
+	fmt.Fprintf(&sb, `  <title>%s: %s</title>`+"\n", label, message)
+	sb.WriteString(`  <linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` + "\n")
+	fmt.Fprintf(&sb, `  <clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`+"\n", width)
+	sb.WriteString(`  <g clip-path="url(#r)">` + "\n")
+	fmt.Fprintf(&sb, `    <rect width="%d" height="20" fill="%s"/>`+"\n", labelWidth, badgeLabelColor)
+	fmt.Fprintf(&sb, `    <rect x="%d" width="%d" height="20" fill="%s"/>`+"\n", labelWidth, messageWidth, badgeMessageColor)
+	fmt.Fprintf(&sb, `    <rect width="%d" height="20" fill="url(#s)"/>`+"\n", width)
+	sb.WriteString(`  </g>` + "\n")
+	sb.WriteString(`  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` + "\n")
+	fmt.Fprintf(&sb, `    <text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text>`+"\n", labelWidth/2, label)
+	fmt.Fprintf(&sb, `    <text x="%d" y="14">%s</text>`+"\n", labelWidth/2, label)
+	fmt.Fprintf(&sb, `    <text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text>`+"\n", labelWidth+messageWidth/2, message)
+	fmt.Fprintf(&sb, `    <text x="%d" y="14">%s</text>`+"\n", labelWidth+messageWidth/2, message)
+	sb.WriteString(`  </g>` + "\n")
+	sb.WriteString(`</svg>` + "\n")
+	return sb.String()
+}
+
+// renderBadgePNG renders a badge as a PNG image, similar to
+// [renderBadgeSVG] but using a fixed-width bitmap font.
+func renderBadgePNG(w io.Writer, label, message string) error {
+	face := basicfont.Face7x13
+	textWidth := func(s string) int {
+		return font.MeasureString(face, s).Ceil()
+	}
+	labelWidth := textWidth(label) + 12
+	messageWidth := textWidth(message) + 12
+	img := image.NewRGBA(image.Rect(0, 0, labelWidth+messageWidth, 20))
+	draw.Draw(img, image.Rect(0, 0, labelWidth, 20), image.NewUniform(color.RGBA{0x55, 0x55, 0x55, 0xff}), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(labelWidth, 0, labelWidth+messageWidth, 20), image.NewUniform(color.RGBA{0x00, 0x7e, 0xc6, 0xff}), image.Point{}, draw.Src)
+	d := font.Drawer{Dst: img, Src: image.White, Face: face}
+	d.Dot = fixed.P(6, 14)
+	d.DrawString(label)
+	d.Dot = fixed.P(labelWidth+6, 14)
+	d.DrawString(message)
+	return png.Encode(w, img)
+}
+
+func init() {
+	rootCmd.AddCommand(badgeCmd)
+	badgeCmd.Flags().StringVar(&badgeFlags.format, "format", badgeFormatSVG, "Image format, one of: svg, png.")
+}
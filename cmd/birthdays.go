@@ -0,0 +1,199 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// birthday is the model of an entry in the birthdays file.
+type birthday struct {
+	Name string `yaml:"name"`
+	// Date is either on the format YYYY-MM-DD or MM-DD.
+	Date string `yaml:"date"`
+}
+
+var birthdaysFlags = struct {
+	file string
+	days int
+}{}
+
+var birthdaysCmd = &cobra.Command{
+	Use:   "birthdays",
+	Short: "Cross-reference your friends' birthdays with their namnsdagar",
+	Long: `Cross-reference your friends' birthdays with their namnsdagar.
+
+Shows whose birthday coincides with their namnsdag, and whose namnsdag or
+birthday is coming up within the next week. The birthdays are read from
+~/.config/namnsdag/birthdays.yaml, or the equivalent in other OS's config
+directories, on the format:
+
+  - name: Anna Svensson
+    date: 1990-12-09
+  - name: Erik
+    date: 05-18
+
+The namnsdag is looked up using the first name.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		birthdays, err := loadBirthdays()
+		if err != nil {
+			return err
+		}
+		cache, err := loadOrFetchNames(now())
+		if err != nil {
+			return err
+		}
+		today := namnsdag.StartOfDay(now())
+		type upcomingDay struct {
+			days int
+			text string
+		}
+		var coinciding []string
+		var upcoming []upcomingDay
+		for _, b := range birthdays {
+			birthDoM, err := parseBirthDate(b.Date)
+			if err != nil {
+				return fmt.Errorf("birthday of %q: %w", b.Name, err)
+			}
+			names := findName(cache, firstName(b.Name))
+			names = filterNames(names)
+			if days := daysUntil(birthDoM, today); days < birthdaysFlags.days {
+				upcoming = append(upcoming, upcomingDay{days, fmt.Sprintf("%s: birthday %s", b.Name, formatDaysUntil(birthDoM, days))})
+			}
+			for _, name := range names {
+				if name.DoM() == birthDoM {
+					coinciding = append(coinciding, fmt.Sprintf("%s: birthday and namnsdag on %s", b.Name, formatDoM(birthDoM)))
+				} else if days := daysUntil(name.DoM(), today); days < birthdaysFlags.days {
+					upcoming = append(upcoming, upcomingDay{days, fmt.Sprintf("%s: namnsdag %s", b.Name, formatDaysUntil(name.DoM(), days))})
+				}
+			}
+		}
+		for _, line := range coinciding {
+			writeColored(line)
+		}
+		if len(upcoming) == 0 {
+			writeColored(colorNameNone.Sprintf("No birthdays or namnsdagar within %s", pluralize(birthdaysFlags.days, "day")))
+			return nil
+		}
+		sort.SliceStable(upcoming, func(i, j int) bool {
+			return upcoming[i].days < upcoming[j].days
+		})
+		for _, u := range upcoming {
+			writeColored(u.text)
+		}
+		return nil
+	},
+}
+
+// birthdaysFile returns the path to the birthdays file, which is stored
+// alongside the config file.
+func birthdaysFile() (string, error) {
+	if birthdaysFlags.file != "" {
+		return birthdaysFlags.file, nil
+	}
+	path, err := configFile()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "birthdays.yaml"), nil
+}
+
+func loadBirthdays() ([]birthday, error) {
+	path, err := birthdaysFile()
+	if err != nil {
+		return nil, fmt.Errorf("get birthdays file path: %w", err)
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no birthdays file found, create one at %s", path)
+	} else if err != nil {
+		return nil, err
+	}
+	var birthdays []birthday
+	if err := yaml.Unmarshal(b, &birthdays); err != nil {
+		return nil, fmt.Errorf("parse birthdays file %q: %w", path, err)
+	}
+	return birthdays, nil
+}
+
+// parseBirthDate parses a date on the format YYYY-MM-DD or MM-DD.
+func parseBirthDate(s string) (namnsdag.DoM, error) {
+	if t, err := parseDate(s); err == nil {
+		return namnsdag.NewDoMFromTime(t), nil
+	}
+	var dom namnsdag.DoM
+	if err := dom.UnmarshalText([]byte(s)); err != nil {
+		return namnsdag.DoM{}, fmt.Errorf("invalid date %q, must be YYYY-MM-DD or MM-DD", s)
+	}
+	if err := dom.Validate(); err != nil {
+		return namnsdag.DoM{}, fmt.Errorf("invalid date %q: %w", s, err)
+	}
+	return dom, nil
+}
+
+func firstName(fullName string) string {
+	fields := strings.Fields(fullName)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// daysUntil returns the number of days until the next occurrence of a day,
+// where 0 means today. See [namnsdag.DoM.NextOccurrence].
+func daysUntil(dom namnsdag.DoM, today time.Time) int {
+	next := dom.NextOccurrence(today)
+	// Counting in UTC, as days are not always 24 hours long in other time
+	// zones, due to daylight saving time.
+	y1, m1, d1 := today.Date()
+	y2, m2, d2 := next.Date()
+	from := time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func formatDaysUntil(dom namnsdag.DoM, days int) string {
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return fmt.Sprintf("tomorrow, %s", formatDoM(dom))
+	default:
+		return fmt.Sprintf("on %s (in %d days)", formatDoM(dom), days)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(birthdaysCmd)
+	birthdaysCmd.Flags().StringVar(&birthdaysFlags.file, "file", "", "Path to birthdays file (default ~/.config/namnsdag/birthdays.yaml).")
+	birthdaysCmd.Flags().IntVar(&birthdaysFlags.days, "days", 7, "Number of days ahead to show upcoming birthdays and namnsdagar for.")
+}
@@ -0,0 +1,204 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+// botCommand is the chat command that the bots answer to.
+const botCommand = "!namnsdag"
+
+// defaultPostAt is the default time of day to post the daily names.
+const defaultPostAt = "08:00"
+
+// botConfig contains the settings of the bots in the config file.
+type botConfig struct {
+	Matrix matrixBotConfig `yaml:"matrix"`
+}
+
+type matrixBotConfig struct {
+	Homeserver  string   `yaml:"homeserver"`
+	User        string   `yaml:"user"`
+	Password    string   `yaml:"password"`
+	AccessToken string   `yaml:"access-token"`
+	Rooms       []string `yaml:"rooms"`
+	At          string   `yaml:"at"`
+}
+
+var botMatrixFlags = matrixBotConfig{}
+
+var botCmd = &cobra.Command{
+	Use:   "bot",
+	Short: "Run a chat bot that posts the daily names and answers queries",
+}
+
+var botMatrixCmd = &cobra.Command{
+	Use:   "matrix",
+	Short: "Run a Matrix bot that posts the daily names and answers queries",
+	Long: `Run a Matrix bot that posts the daily names and answers queries.
+
+The bot logs into the Matrix homeserver, joins the rooms, and posts the names
+to the rooms every day at the given time, in the time zone from --timezone.
+It also answers the following commands in all rooms it has joined:
+
+  !namnsdag             Today's names.
+  !namnsdag <name>      Which days a name is celebrated.
+  !namnsdag YYYY-MM-DD  The names of a given day.
+
+The bot logs in using either an access token, or a password, set in the
+"bot.matrix" section of the config file, or in the
+NAMNSDAG_BOT_MATRIX_ACCESS_TOKEN or NAMNSDAG_BOT_MATRIX_PASSWORD environment
+variables.`,
+	Example: `  NAMNSDAG_BOT_MATRIX_PASSWORD=secret namnsdag bot matrix \
+    --homeserver https://matrix.example.com --user namnsdag \
+    --room '#family:example.com' --at 07:30`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings := matrixBotSettings(cmd)
+		if settings.Homeserver == "" {
+			return errors.New("missing homeserver, set using --homeserver")
+		}
+		if len(settings.Rooms) == 0 {
+			return errors.New("missing rooms, set using --room")
+		}
+		at, err := parseTimeOfDay(settings.At)
+		if err != nil {
+			return err
+		}
+		l, err := lookupSentenceLang(rootFlags.lang)
+		if err != nil {
+			return err
+		}
+		client, err := newHTTPClient()
+		if err != nil {
+			return err
+		}
+		httpClient := *client
+		httpClient.Timeout = 2 * matrixSyncTimeout
+		m := newMatrixClient(settings.Homeserver, &httpClient)
+		switch {
+		case settings.AccessToken != "":
+			m.accessToken = settings.AccessToken
+			err = m.whoami()
+		case settings.User != "" && settings.Password != "":
+			err = m.login(settings.User, settings.Password)
+		default:
+			return errors.New("missing credentials, set either an access token, or a user and password")
+		}
+		if err != nil {
+			return fmt.Errorf("log in to %s: %w", settings.Homeserver, err)
+		}
+		colorStatus.Fprintf(stderr, "Logged in to %s as %s\n", settings.Homeserver, m.userID)
+
+		var roomIDs []string
+		for _, room := range settings.Rooms {
+			roomID, err := m.join(room)
+			if err != nil {
+				return fmt.Errorf("join room %q: %w", room, err)
+			}
+			roomIDs = append(roomIDs, roomID)
+		}
+		store, err := loadStore(cmd.Context())
+		if err != nil {
+			return err
+		}
+		go runDaily(at, func(day time.Time) error {
+			text := l.sentence(storeNames(store, day), day, now())
+			for _, roomID := range roomIDs {
+				if err := m.send(roomID, text); err != nil {
+					return fmt.Errorf("post names to %s: %w", roomID, err)
+				}
+			}
+			return nil
+		})
+		return m.listen(func(roomID, body string) {
+			reply, ok := botReply(store, l, body)
+			if !ok {
+				return
+			}
+			if err := m.send(roomID, reply); err != nil {
+				writeWarning(fmt.Errorf("reply in %s: %w", roomID, err))
+			}
+		})
+	},
+}
+
+// matrixBotSettings returns the settings of the Matrix bot, where the flags
+// take precedence over the config file.
+func matrixBotSettings(cmd *cobra.Command) matrixBotConfig {
+	settings := cfg.Bot.Matrix
+	flags := cmd.Flags()
+	if flags.Changed("homeserver") {
+		settings.Homeserver = botMatrixFlags.Homeserver
+	}
+	if flags.Changed("user") {
+		settings.User = botMatrixFlags.User
+	}
+	if flags.Changed("room") {
+		settings.Rooms = botMatrixFlags.Rooms
+	}
+	if flags.Changed("at") || settings.At == "" {
+		settings.At = botMatrixFlags.At
+	}
+	return settings
+}
+
+// storeNames returns the names of the given day, filtered by the names
+// filter.
+func storeNames(store *namnsdag.Store, day time.Time) []namnsdag.Name {
+	return filterNames(store.Names(namnsdag.NewDoMFromTime(day)))
+}
+
+// botReply returns the reply to a chat message, or false if the message is
+// not a command to the bot. See [botCommand].
+func botReply(store *namnsdag.Store, l sentenceLang, message string) (string, bool) {
+	fields := strings.Fields(message)
+	if len(fields) == 0 || fields[0] != botCommand {
+		return "", false
+	}
+	query := strings.Join(fields[1:], " ")
+	if query == "" {
+		today := now()
+		return l.sentence(storeNames(store, today), today, today), true
+	}
+	if day, err := parseDate(query); err == nil {
+		return l.sentence(storeNames(store, day), day, now()), true
+	}
+	names := filterNames(findName(store.Cache(), query))
+	return l.nameDaySentence(query, names), true
+}
+
+func init() {
+	rootCmd.AddCommand(botCmd)
+	botCmd.AddCommand(botMatrixCmd)
+
+	botMatrixCmd.Flags().StringVar(&botMatrixFlags.Homeserver, "homeserver", "", `URL of the Matrix homeserver, eg. "https://matrix.example.com".`)
+	botMatrixCmd.Flags().StringVar(&botMatrixFlags.User, "user", "", "User to log in as, when logging in using a password.")
+	botMatrixCmd.Flags().StringSliceVar(&botMatrixFlags.Rooms, "room", nil, `Rooms to post the daily names to, by ID or alias, eg. "#family:example.com".`)
+	botMatrixCmd.Flags().StringVar(&botMatrixFlags.At, "at", defaultPostAt, "Time of day to post the daily names, on the format HH:MM.")
+}
@@ -0,0 +1,302 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the cached names",
+}
+
+var cacheMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrate the cache from older versions of namnsdag",
+	Long: `Migrate the cache from older versions of namnsdag.
+
+Reads the cache files written by namnsdag v2 and earlier (cache@v2.json and
+latest.json) and writes their names into the current cache file. The old files
+are left untouched.
+
+This is done automatically once, when no current cache file exists.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if namnsdag.ReadOnlyCache {
+			return namnsdag.ErrCacheReadOnly
+		}
+		cache, err := namnsdag.MigrateCache()
+		if errors.Is(err, namnsdag.ErrNoLegacyCache) {
+			colorStatus.Println("Nothing to migrate, no cache from older versions found.")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		writeColored(fmt.Sprintf("Migrated names for %d days", len(cache.NamesPerDay)))
+		return nil
+	},
+}
+
+var cacheBackupCmd = &cobra.Command{
+	Use:   "backup <file>",
+	Short: "Write a copy of the cached names to a file",
+	Long: `Write a copy of the cached names to a file.
+
+The backup can later be restored using "namnsdag cache restore", for example
+to move a warmed-up cache onto a machine without internet access.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := loadCache()
+		if err != nil {
+			return fmt.Errorf("load cached names: %w", err)
+		}
+		if err := cache.Validate(); err != nil {
+			return fmt.Errorf("validate cached names: %w", err)
+		}
+		file, err := os.Create(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if err := namnsdag.WriteCache(file, cache); err != nil {
+			return fmt.Errorf("write backup: %w", err)
+		}
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("write backup: %w", err)
+		}
+		writeColored(fmt.Sprintf("Backed up names for %d days to %s", len(cache.NamesPerDay), args[0]))
+		return nil
+	},
+}
+
+var cacheRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Replace the cached names with a backup",
+	Long: `Replace the cached names with a backup.
+
+The backup is validated before it is restored, and the current cache is left
+untouched if the backup is invalid.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if namnsdag.ReadOnlyCache {
+			return namnsdag.ErrCacheReadOnly
+		}
+		file, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		cache, err := namnsdag.ReadCache(file)
+		if err != nil {
+			return fmt.Errorf("read backup: %w", err)
+		}
+		if err := cache.Validate(); err != nil {
+			return fmt.Errorf("validate backup: %w", err)
+		}
+		if cache.UpdatedAt.IsZero() && !cache.Partial {
+			// Backups without a timestamp are treated as freshly fetched
+			cache.UpdatedAt = now()
+		}
+		if err := saveCache(cache); err != nil {
+			return fmt.Errorf("save cache: %w", err)
+		}
+		writeColored(fmt.Sprintf("Restored names for %d days from %s", len(cache.NamesPerDay), args[0]))
+		return nil
+	},
+}
+
+var cacheVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check if the cached names are up-to-date with upstream",
+	Long: `Check if the cached names are up-to-date with upstream.
+
+Sends a conditional HTTP request using the ETag of the cached names, and exits
+with a non-zero exit code if the upstream names have changed. The cache is
+left untouched.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := loadCache()
+		if err != nil {
+			return fmt.Errorf("load cached names: %w", err)
+		}
+		if cache.ETag == "" {
+			return errors.New("cached names have no ETag to verify against")
+		}
+		req, err := newRequest(cache.ETag)
+		if err != nil {
+			return err
+		}
+		source, err := nameSource()
+		if err != nil {
+			return err
+		}
+		resp, err := source.Fetch(req)
+		if errors.Is(err, namnsdag.ErrHTTPNotModified) {
+			writeColored(fmt.Sprintf("Cache is up-to-date with %s (ETag %s)", source, cache.ETag))
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("fetch names: %w", err)
+		}
+		return fmt.Errorf("cache is outdated: cached ETag %s, but upstream has ETag %s", cache.ETag, resp.ETag)
+	},
+}
+
+var cacheInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show information about the cached names",
+	Long: `Show information about the cached names.
+
+Shows where the cache is stored, where and when its names were fetched, and
+which days are stale, meaning their names have not been fetched today. Days
+can be fetched one at a time using --light, possibly from different sources,
+so the days may differ in freshness.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := cacheFile()
+		if err != nil {
+			return err
+		}
+		cache, err := loadCache()
+		if err != nil {
+			return fmt.Errorf("load cached names: %w", err)
+		}
+		var numNames int
+		for _, names := range cache.NamesPerDay {
+			numNames += len(names)
+		}
+		writeColored(fmt.Sprintf("File: %s", path))
+		if defaultPath, err := namnsdag.CacheFile(); err == nil {
+			writeColored(fmt.Sprintf("Directory: %s (from %s)", filepath.Dir(defaultPath), cacheDirOrigin()))
+		}
+		writeColored(fmt.Sprintf("Names: %d, on %s", numNames, pluralize(len(cache.NamesPerDay), "day")))
+		if cache.Partial {
+			writeColored("Updated: only partially, one day at a time")
+		} else if cache.UpdatedAt.IsZero() {
+			writeColored("Updated: never")
+		} else {
+			source := cache.Source
+			if source == "" {
+				source = "unknown source"
+			}
+			writeColored(fmt.Sprintf("Updated: %s (%s) from %s",
+				inLocation(cache.UpdatedAt).Format("2006-01-02 15:04"),
+				formatAge(now().Sub(cache.UpdatedAt)),
+				source))
+		}
+		if cache.ETag != "" {
+			writeColored(fmt.Sprintf("ETag: %s", cache.ETag))
+		}
+		sources := map[string]int{}
+		for _, info := range cache.Days {
+			sources[info.Source]++
+		}
+		for _, source := range sortedKeys(sources) {
+			writeColored(fmt.Sprintf("Partially updated from %s: %s", source, pluralize(sources[source], "day")))
+		}
+
+		today := namnsdag.StartOfDay(now())
+		stale := cache.StaleDays(today)
+		if len(stale) == 0 {
+			writeColored("Stale days: none")
+			return nil
+		}
+		writeColored(fmt.Sprintf("Stale days: %d of %d (%s)",
+			len(stale), len(namnsdag.AllDoMs()), formatDoMRanges(stale)))
+		return nil
+	},
+}
+
+// cacheDirOrigin describes where the cache directory comes from, such as
+// %LOCALAPPDATA% on Windows. See [os.UserCacheDir].
+func cacheDirOrigin() string {
+	if cfg.CacheDir != "" {
+		return "cache-dir config"
+	}
+	switch runtime.GOOS {
+	case "windows":
+		if os.Getenv("LocalAppData") == "" {
+			return `%USERPROFILE%\AppData\Local, as %LOCALAPPDATA% is unset`
+		}
+		return "%LOCALAPPDATA%"
+	case "darwin", "ios":
+		return "~/Library/Caches"
+	case "plan9":
+		return "$home/lib/cache"
+	}
+	if os.Getenv("XDG_CACHE_HOME") != "" {
+		return "$XDG_CACHE_HOME"
+	}
+	return "~/.cache"
+}
+
+// formatDoMRanges formats a sorted list of days as ranges of consecutive
+// days, such as "01-01..01-31, 03-05".
+func formatDoMRanges(doms []namnsdag.DoM) string {
+	all := namnsdag.AllDoMs()
+	index := make(map[namnsdag.DoM]int, len(all))
+	for i, dom := range all {
+		index[dom] = i
+	}
+	var ranges []string
+	for i := 0; i < len(doms); {
+		j := i
+		for j+1 < len(doms) && index[doms[j+1]] == index[doms[j]]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, doms[i].String())
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%s..%s", doms[i], doms[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, ", ")
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheInfoCmd)
+	cacheCmd.AddCommand(cacheMigrateCmd)
+	cacheCmd.AddCommand(cacheBackupCmd)
+	cacheCmd.AddCommand(cacheRestoreCmd)
+	cacheCmd.AddCommand(cacheVerifyCmd)
+}
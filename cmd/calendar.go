@@ -0,0 +1,105 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+)
+
+// calendarMonth is the layout of a month in the printable calendars, shared
+// by all output formats.
+type calendarMonth struct {
+	Title string
+	Days  []calendarDay
+}
+
+// calendarDay is the layout of a day in the printable calendars.
+type calendarDay struct {
+	Date     time.Time
+	Weekday  string
+	Week     int    // ISO week number
+	WeekText string // localized week number, only set on Mondays with --week-numbers
+	Names    []namnsdag.Name
+	Holidays []namnsdag.Holiday
+	// RedDay is true on Sundays and public holidays, which are printed in red
+	// as in Swedish calendars.
+	RedDay bool
+}
+
+// HolidayNames returns the names of the day's holidays, joined by comma.
+func (d calendarDay) HolidayNames() string {
+	names := make([]string, len(d.Holidays))
+	for i, holiday := range d.Holidays {
+		names[i] = holiday.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// newCalendarMonth lays out the days of a month, with the names to show on
+// each day.
+func newCalendarMonth(cache namnsdag.Cache, year int, month time.Month, lang sentenceLang) calendarMonth {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, location)
+	m := calendarMonth{
+		Title: capitalize(lang.months[month-1]),
+	}
+	for day := first; day.Month() == month; day = day.AddDate(0, 0, 1) {
+		holidays := namnsdag.HolidaysOn(day)
+		_, week := day.ISOWeek()
+		d := calendarDay{
+			Date:     day,
+			Weekday:  lang.weekdays[day.Weekday()],
+			Week:     week,
+			Names:    filterNames(cache.NamesPerDay[namnsdag.NewDoMFromTime(day)]),
+			Holidays: holidays,
+			RedDay:   day.Weekday() == time.Sunday,
+		}
+		if rootFlags.weekNumbers && day.Weekday() == time.Monday {
+			d.WeekText = fmt.Sprintf(lang.week, week)
+		}
+		for _, holiday := range holidays {
+			if holiday.RedDay {
+				d.RedDay = true
+			}
+		}
+		m.Days = append(m.Days, d)
+	}
+	return m
+}
+
+// newCalendarYear lays out all months of a year.
+func newCalendarYear(cache namnsdag.Cache, year int, lang sentenceLang) []calendarMonth {
+	months := make([]calendarMonth, 0, 12)
+	for month := time.January; month <= time.December; month++ {
+		months = append(months, newCalendarMonth(cache, year, month, lang))
+	}
+	return months
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
@@ -0,0 +1,99 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// clipboardCommands returns the commands that copy their stdin to the system
+// clipboard on this OS, in order of preference.
+func clipboardCommands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{
+			// clip.exe garbles non-ASCII characters, such as "å", "ä", and
+			// "ö", so PowerShell is preferred, told to read stdin as UTF-8.
+			{"powershell.exe", "-NoProfile", "-NonInteractive", "-Command",
+				"[Console]::InputEncoding = [Text.Encoding]::UTF8; Set-Clipboard -Value ([Console]::In.ReadToEnd())"},
+			{"clip.exe"},
+		}
+	case "android":
+		return [][]string{{"termux-clipboard-set"}}
+	}
+	var commands [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		commands = append(commands, []string{"wl-copy"})
+	}
+	return append(commands,
+		[]string{"xclip", "-selection", "clipboard"},
+		[]string{"xsel", "--clipboard", "--input"})
+}
+
+// copyToClipboard copies the text to the system clipboard, using the first
+// of [clipboardCommands] that is installed.
+func copyToClipboard(text string) error {
+	var names []string
+	for _, command := range clipboardCommands() {
+		path, err := exec.LookPath(command[0])
+		if errors.Is(err, exec.ErrNotFound) {
+			names = append(names, command[0])
+			continue
+		} else if err != nil {
+			return fmt.Errorf("copy to clipboard: %w", err)
+		}
+		c := exec.Command(path, command[1:]...)
+		c.Stdin = strings.NewReader(text)
+		c.Stderr = stderr
+		if err := c.Run(); err != nil {
+			return fmt.Errorf("copy to clipboard: %s: %w", command[0], err)
+		}
+		colorStatus.Fprintln(stderr, "Copied to the clipboard.")
+		return nil
+	}
+	return fmt.Errorf("copy to clipboard: found no clipboard command, install one of: %s", strings.Join(names, ", "))
+}
+
+// ansiEscapes matches the escape sequences used to color the output.
+var ansiEscapes = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// recordOutput makes everything written to stdout also be recorded, such as
+// for --copy. The returned function stops the recording, and returns the
+// recorded text without any colors.
+func recordOutput() (stop func() string) {
+	var buf bytes.Buffer
+	out := stdout
+	stdout = io.MultiWriter(out, &buf)
+	return func() string {
+		stdout = out
+		return ansiEscapes.ReplaceAllString(buf.String(), "")
+	}
+}
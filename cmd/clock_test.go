@@ -0,0 +1,112 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag/namnsdagtest"
+)
+
+// The tests use dates long before the system clock, to make sure that the
+// durations are measured using the injected clock.
+
+func TestRootCmdShowUpdatedAge(t *testing.T) {
+	updatedAt := time.Date(2020, time.October, 17, 10, 0, 0, 0, time.UTC)
+	now := updatedAt.Add(2 * time.Hour)
+	out, err := runCmd(t, []string{"--show-updated"},
+		WithClock(namnsdag.FixedClock(now)),
+		WithStore(namnsdag.NewStore(namnsdagtest.Cache(updatedAt, testNames...))))
+	if err != nil {
+		t.Fatalf("execute: %s\n%s", err, out)
+	}
+	if want := "(2 hours ago)"; !strings.Contains(out, want) {
+		t.Errorf("want %q in output, got:\n%s", want, out)
+	}
+}
+
+func TestInfoCmdNameStatsAgeUsesClock(t *testing.T) {
+	isolateDirs(t)
+	fetchedAt := time.Date(2020, time.October, 17, 10, 0, 0, 0, time.UTC)
+	path, err := namnsdag.NameStatsCacheFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := namnsdag.NameStatsTable{
+		Year:      2019,
+		FetchedAt: fetchedAt,
+		Names:     []namnsdag.NameCount{{Name: "Henrik", Count: 40000}},
+	}
+	if err := namnsdag.SaveNameStatsTable(path, table); err != nil {
+		t.Fatal(err)
+	}
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		http.Error(w, "unexpected fetch", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	defer func(url string) { namnsdag.SCBNameStatsURL = url }(namnsdag.SCBNameStatsURL)
+	namnsdag.SCBNameStatsURL = srv.URL
+
+	now := fetchedAt.Add(24 * time.Hour)
+	out, err := execCmd(t, []string{"info", "Henrik"},
+		WithClock(namnsdag.FixedClock(now)),
+		WithStore(namnsdag.NewStore(namnsdagtest.Cache(now, testNames...))))
+	if err != nil {
+		t.Fatalf("execute: %s\n%s", err, out)
+	}
+	if want := "rank 1 (SCB, 2019)"; !strings.Contains(out, want) {
+		t.Errorf("want cached popularity %q in output, got:\n%s", want, out)
+	}
+	if hits != 0 {
+		t.Errorf("want no fetch of fresh statistics, got %d", hits)
+	}
+}
+
+func TestUpdateCheckIntervalUsesClock(t *testing.T) {
+	isolateDirs(t)
+	t.Setenv("NAMNSDAG_CHECK_FOR_UPDATES", "true")
+	defer func(v string) { buildVersion = v }(buildVersion)
+	buildVersion = "v3.0.0"
+	checkedAt := time.Date(2020, time.October, 17, 10, 0, 0, 0, time.UTC)
+	NewRootCmd()
+	if err := saveState(stateUpdateCheck, updateCheck{CheckedAt: checkedAt, LatestVersion: "v3.1.0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A refetch would fail in the tests, and then skip the notice
+	now := checkedAt.Add(24 * time.Hour)
+	out, err := execCmd(t, nil,
+		WithClock(namnsdag.FixedClock(now)),
+		WithStore(namnsdag.NewStore(namnsdagtest.Cache(now, testNames...))))
+	if err != nil {
+		t.Fatalf("execute: %s\n%s", err, out)
+	}
+	if want := "A new version of namnsdag is available: v3.1.0"; !strings.Contains(out, want) {
+		t.Errorf("want %q in output, got:\n%s", want, out)
+	}
+}
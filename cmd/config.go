@@ -0,0 +1,299 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// config is the model of the config file. Any values set via flags take
+// precedence over the values in the config file.
+type config struct {
+	Source        string `yaml:"source"`
+	Dataset       string `yaml:"dataset"`
+	PublicKey     string `yaml:"public-key"`
+	Proxy         string `yaml:"proxy"`
+	CompressCache bool   `yaml:"compress-cache"`
+	CacheReadOnly bool   `yaml:"cache-read-only"`
+	CacheDir      string `yaml:"cache-dir"`
+	StateDir      string `yaml:"state-dir"`
+	UserAgent     string `yaml:"user-agent"`
+	Lang          string `yaml:"lang"`
+	FirstWeekday  string `yaml:"first-weekday"`
+	Timezone      string `yaml:"timezone"`
+	WeekNumbers   bool   `yaml:"week-numbers"`
+
+	Decorations []string          `yaml:"decorations"`
+	Colors      map[string]string `yaml:"colors"`
+	MaxWidth    int               `yaml:"max-width"`
+	Separator   string            `yaml:"separator"`
+
+	MinFetchInterval time.Duration `yaml:"min-fetch-interval"`
+	CheckForUpdates  bool          `yaml:"check-for-updates"`
+
+	KeepDuplicateNames bool   `yaml:"keep-duplicate-names"`
+	NamesFilter        string `yaml:"names-filter"`
+	Strict             bool   `yaml:"strict"`
+	AsyncRefresh       bool   `yaml:"async-refresh"`
+	ShowGender         bool   `yaml:"show-gender"`
+	ShowHolidays       bool   `yaml:"show-holidays"`
+	ShowThemeDays      bool   `yaml:"show-theme-days"`
+
+	ThemeDaysSource string `yaml:"theme-days-source"`
+	GreetTemplate   string `yaml:"greet-template"`
+
+	Aliases          namnsdag.Aliases `yaml:"aliases"`
+	NoDefaultAliases bool             `yaml:"no-default-aliases"`
+
+	Hooks  hooksConfig  `yaml:"hooks"`
+	Serve  serveConfig  `yaml:"serve"`
+	Bot    botConfig    `yaml:"bot"`
+	Notify notifyConfig `yaml:"notify"`
+
+	Profiles map[string]profileConfig `yaml:"profiles"`
+}
+
+// profileConfig contains the settings of a named profile, selected using
+// --profile, which take precedence over the same settings in the top level of
+// the config file.
+type profileConfig struct {
+	Source    string `yaml:"source"`
+	Dataset   string `yaml:"dataset"`
+	PublicKey string `yaml:"public-key"`
+}
+
+var cfg = config{
+	MinFetchInterval: namnsdag.DefaultMinFetchInterval,
+}
+
+// configFile returns the path to the config file, found in
+// ~/.config/namnsdag/config.yaml or the equivalent in other OS's config
+// directories (eg. %APPDATA%).
+func configFile() (string, error) {
+	if rootFlags.configFile != "" {
+		return rootFlags.configFile, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "namnsdag", "config.yaml"), nil
+}
+
+func loadConfig(cmd *cobra.Command) error {
+	if !rootFlags.stateless {
+		if err := loadConfigFile(); err != nil {
+			return err
+		}
+	}
+	if err := loadEnvConfig(reflect.ValueOf(&cfg).Elem(), envConfigPrefix); err != nil {
+		return err
+	}
+	if err := applyTheme(cfg.Colors); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	if profile, ok := cfg.Profiles[rootFlags.profile]; ok && rootFlags.profile != "" {
+		if profile.Source != "" || profile.Dataset != "" {
+			cfg.Source = profile.Source
+			cfg.Dataset = profile.Dataset
+		}
+		if profile.PublicKey != "" {
+			cfg.PublicKey = profile.PublicKey
+		}
+	}
+
+	flags := cmd.Flags()
+	if !flags.Changed("dataset") && !flags.Changed("source") {
+		rootFlags.dataset = cfg.Dataset
+		rootFlags.source = cfg.Source
+	}
+	if !flags.Changed("public-key") {
+		rootFlags.publicKey = cfg.PublicKey
+	}
+	if !flags.Changed("proxy") {
+		rootFlags.proxy = cfg.Proxy
+	}
+	if !flags.Changed("compress-cache") {
+		namnsdag.CompressCache = cfg.CompressCache
+	}
+	if !flags.Changed("cache-read-only") {
+		namnsdag.ReadOnlyCache = cfg.CacheReadOnly
+	}
+	namnsdag.CacheDir = cfg.CacheDir
+	namnsdag.StateDir = cfg.StateDir
+	if !flags.Changed("holidays") {
+		rootFlags.holidays = cfg.ShowHolidays
+	}
+	if !flags.Changed("theme-days") {
+		rootFlags.themeDays = cfg.ShowThemeDays
+	}
+	if !flags.Changed("decorations") && cfg.Decorations != nil {
+		rootFlags.decorations = cfg.Decorations
+	}
+	if !flags.Changed("max-width") && cfg.MaxWidth != 0 {
+		rootFlags.maxWidth = cfg.MaxWidth
+	}
+	if !flags.Changed("separator") && cfg.Separator != "" {
+		rootFlags.separator = cfg.Separator
+	}
+	if !flags.Changed("lang") && cfg.Lang != "" {
+		rootFlags.lang = cfg.Lang
+	}
+	if !flags.Changed("timezone") {
+		rootFlags.timezone = cfg.Timezone
+	}
+	if !flags.Changed("week-numbers") {
+		rootFlags.weekNumbers = cfg.WeekNumbers
+	}
+	if !flags.Changed("strict") {
+		rootFlags.strict = cfg.Strict
+	}
+	if !flags.Changed("async-refresh") {
+		rootFlags.asyncRefresh = cfg.AsyncRefresh
+	}
+	if !flags.Changed("show-gender") {
+		rootFlags.showGender = cfg.ShowGender
+	}
+	return nil
+}
+
+func loadConfigFile() error {
+	path, err := configFile()
+	if err != nil {
+		return fmt.Errorf("get config file path: %w", err)
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && rootFlags.configFile == "" {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return fmt.Errorf("parse config file %q: %w", path, err)
+	}
+	return nil
+}
+
+// envConfigPrefix is the prefix of the environment variables that override
+// the config file, such as NAMNSDAG_TIMEZONE for "timezone".
+const envConfigPrefix = "NAMNSDAG_"
+
+// loadEnvConfig overrides the config with values from environment variables,
+// named after the config keys in upper snake case, such as
+// NAMNSDAG_MIN_FETCH_INTERVAL for "min-fetch-interval", and
+// NAMNSDAG_SERVE_LISTEN for "listen" inside "serve". The values are parsed as
+// YAML, so lists and maps are written as eg. "[prefix, marker]".
+func loadEnvConfig(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		name := prefix + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := loadEnvConfig(field, name+"_"); err != nil {
+				return err
+			}
+			continue
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := yaml.Unmarshal([]byte(value), field.Addr().Interface()); err != nil {
+			return fmt.Errorf("parse environment variable %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// userAgent returns the HTTP User-Agent header to send when fetching, which
+// includes this program's version, unless overridden by the config file.
+func userAgent() string {
+	if cfg.UserAgent != "" {
+		return cfg.UserAgent
+	}
+	return fmt.Sprintf("namnsdag/%s (+https://github.com/jilleJr/namnsdag)", version())
+}
+
+// newRequest creates a request for fetching names, using the HTTP client,
+// User-Agent, and strictness from the flags and config.
+func newRequest(etag string) (namnsdag.Request, error) {
+	client, err := newHTTPClient()
+	if err != nil {
+		return namnsdag.Request{}, err
+	}
+	return namnsdag.Request{
+		ETag:       etag,
+		HTTPClient: client,
+		UserAgent:  userAgent(),
+		Strict:     rootFlags.strict,
+		Clock:      clock,
+	}, nil
+}
+
+// newHTTPClient creates a HTTP client that respects the --proxy flag.
+// Without a proxy set, the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment
+// variables are used instead.
+//
+// With the --record-fixtures flag set, all responses are also saved to disk,
+// and with --verbose, the timings of all requests are written to stderr.
+func newHTTPClient() (*http.Client, error) {
+	var transport http.RoundTripper = http.DefaultTransport
+	if rootFlags.proxy != "" {
+		proxyURL, err := url.Parse(rootFlags.proxy)
+		if err != nil {
+			return nil, fmt.Errorf("parse proxy URL: %w", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q, must be one of: http, https, socks5", proxyURL.Scheme)
+		}
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.Proxy = http.ProxyURL(proxyURL)
+		transport = t
+	}
+	if rootFlags.verbose {
+		transport = tracingTransport{Next: transport}
+	}
+	if rootFlags.recordFixtures != "" {
+		transport = namnsdag.RecordingTransport{
+			Dir:  rootFlags.recordFixtures,
+			Next: transport,
+		}
+	}
+	return &http.Client{Transport: transport}, nil
+}
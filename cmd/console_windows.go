@@ -0,0 +1,55 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/fatih/color"
+	"golang.org/x/sys/windows"
+)
+
+// setupConsole enables virtual terminal processing on the Windows console,
+// so that it interprets the ANSI escape sequences used for colors instead of
+// printing them as-is. Colors are disabled if the console does not support
+// it, such as on Windows versions older than Windows 10.
+func setupConsole() {
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		if !enableVirtualTerminal(f) {
+			color.NoColor = true
+		}
+	}
+}
+
+// enableVirtualTerminal enables virtual terminal processing on the console,
+// and reports whether it succeeded. Files that are not consoles, such as
+// when redirected to a file, are left as-is, and are reported as succeeded.
+func enableVirtualTerminal(f *os.File) bool {
+	h := windows.Handle(f.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(h, &mode); err != nil {
+		return true
+	}
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return true
+	}
+	return windows.SetConsoleMode(h, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+}
@@ -0,0 +1,350 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime/quotedprintable"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var contactsFlags = struct {
+	carddav  string
+	username string
+}{}
+
+var contactsCmd = &cobra.Command{
+	Use:   "contacts",
+	Short: "Manage the watch list of names from your contacts",
+	Long: `Manage the watch list of names from your contacts.
+
+When any of today's names are on the watch list, the contacts carrying the
+name are shown alongside the names. The watch list is stored in
+~/.config/namnsdag/watchlist.yaml, or the equivalent in other OS's config
+directories, and can also be edited by hand.`,
+}
+
+var contactsImportCmd = &cobra.Command{
+	Use:   "import [file.vcf]",
+	Short: "Add the given names of your contacts to the watch list",
+	Long: `Add the given names of your contacts to the watch list.
+
+Contacts are read from a vCard file, as exported by most address books, or
+from a CardDAV address book using --carddav. The password for CardDAV is read
+from the NAMNSDAG_CARDDAV_PASSWORD environment variable.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var cards []vCard
+		switch {
+		case len(args) == 1 && contactsFlags.carddav != "":
+			return fmt.Errorf("cannot use both a file and --carddav at the same time")
+		case len(args) == 1:
+			file, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			cards, err = parseVCards(file)
+			if err != nil {
+				return fmt.Errorf("parse vCard file: %w", err)
+			}
+		case contactsFlags.carddav != "":
+			var err error
+			cards, err = fetchCardDAV(contactsFlags.carddav)
+			if err != nil {
+				return fmt.Errorf("fetch contacts from CardDAV: %w", err)
+			}
+		default:
+			return fmt.Errorf("requires either a vCard file or --carddav")
+		}
+
+		var entries []watchEntry
+		for _, card := range cards {
+			for _, name := range card.givenNames() {
+				entries = append(entries, watchEntry{Name: name, Contact: card.FullName})
+			}
+		}
+		list, err := loadWatchList()
+		if err != nil {
+			return err
+		}
+		list, added := addToWatchList(list, entries...)
+		if err := saveWatchList(list); err != nil {
+			return fmt.Errorf("save watch list: %w", err)
+		}
+		writeColored(fmt.Sprintf("Added %s from %s to the watch list",
+			pluralize(added, "name"), pluralize(len(cards), "contact")))
+		return nil
+	},
+}
+
+var contactsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the names on the watch list",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, err := loadWatchList()
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			writeColored(colorNameNone.Sprint("The watch list is empty"))
+			return nil
+		}
+		defer startPager()()
+		for _, e := range list {
+			if e.Contact != "" && e.Contact != e.Name {
+				writeColored(fmt.Sprintf("%s %s", colorNameOfficial.Sprint(e.Name), colorStatus.Sprintf("(%s)", e.Contact)))
+			} else {
+				writeColored(colorNameOfficial.Sprint(e.Name))
+			}
+		}
+		return nil
+	},
+}
+
+// vCard contains the fields of a contact needed for the watch list.
+type vCard struct {
+	// FullName is the FN property.
+	FullName string
+	// GivenNames is the given and additional names from the N property.
+	GivenNames []string
+}
+
+// givenNames returns the given names of the contact, falling back to the
+// first word of the full name.
+func (c vCard) givenNames() []string {
+	if len(c.GivenNames) > 0 {
+		return c.GivenNames
+	}
+	if name := firstName(c.FullName); name != "" {
+		return []string{name}
+	}
+	return nil
+}
+
+// parseVCards parses all contacts in a vCard file, as defined in RFC 6350,
+// only reading the FN and N properties. Quoted-printable values from vCard
+// 2.1, as exported by many phones, are decoded.
+func parseVCards(r io.Reader) ([]vCard, error) {
+	lines, err := unfoldVCardLines(r)
+	if err != nil {
+		return nil, err
+	}
+	var cards []vCard
+	var card *vCard
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// Ignore any group and parameters, eg. "item1.N;CHARSET=UTF-8",
+		// except the encoding used by vCard 2.1
+		name, params, _ := strings.Cut(name, ";")
+		if i := strings.LastIndexByte(name, '.'); i != -1 {
+			name = name[i+1:]
+		}
+		if isQuotedPrintable(params) {
+			value = decodeQuotedPrintable(value)
+		}
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VCARD") {
+				card = &vCard{}
+			}
+		case "END":
+			if strings.EqualFold(value, "VCARD") && card != nil {
+				cards = append(cards, *card)
+				card = nil
+			}
+		case "FN":
+			if card != nil {
+				card.FullName = unescapeVCard(value)
+			}
+		case "N":
+			if card == nil {
+				continue
+			}
+			// Family;Given;Additional;Prefixes;Suffixes
+			parts := splitVCard(value, ';')
+			for _, i := range []int{1, 2} {
+				if i >= len(parts) {
+					continue
+				}
+				for _, given := range splitVCard(parts[i], ',') {
+					card.GivenNames = append(card.GivenNames, strings.Fields(unescapeVCard(given))...)
+				}
+			}
+		}
+	}
+	return cards, nil
+}
+
+// unfoldVCardLines reads all lines, where lines starting with a space or tab
+// are continuations of the previous line. Quoted-printable values, as used by
+// vCard 2.1, are instead continued on the next line by ending the line with
+// a "=", which is called a soft line break.
+func unfoldVCardLines(r io.Reader) ([]string, error) {
+	var lines []string
+	softBreak := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		switch {
+		case softBreak:
+			lines[len(lines)-1] += line
+		case (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0:
+			lines[len(lines)-1] += line[1:]
+		default:
+			lines = append(lines, line)
+		}
+		last := lines[len(lines)-1]
+		softBreak = false
+		if name, _, ok := strings.Cut(last, ":"); ok && strings.HasSuffix(last, "=") {
+			_, params, _ := strings.Cut(name, ";")
+			if isQuotedPrintable(params) {
+				lines[len(lines)-1] = strings.TrimSuffix(last, "=")
+				softBreak = true
+			}
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// isQuotedPrintable reports whether the parameters of a vCard property, such
+// as "CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE", mark its value as
+// quoted-printable. vCard 2.1 also allows the bare "QUOTED-PRINTABLE".
+func isQuotedPrintable(params string) bool {
+	for _, param := range strings.Split(params, ";") {
+		param = strings.ToUpper(strings.TrimSpace(param))
+		if param == "ENCODING=QUOTED-PRINTABLE" || param == "QUOTED-PRINTABLE" {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeQuotedPrintable decodes a quoted-printable value, such as
+// "G=C3=B6sta", whose soft line breaks have already been removed. Values
+// that are not valid quoted-printable are returned as-is.
+func decodeQuotedPrintable(value string) string {
+	b, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(value)))
+	if err != nil {
+		return value
+	}
+	return string(b)
+}
+
+// splitVCard splits a value on a separator that is not escaped.
+func splitVCard(value string, sep byte) []string {
+	var parts []string
+	var start int
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+var vCardUnescaper = strings.NewReplacer(`\,`, ",", `\;`, ";", `\\`, `\`, `\n`, " ", `\N`, " ")
+
+func unescapeVCard(value string) string {
+	return strings.TrimSpace(vCardUnescaper.Replace(value))
+}
+
+// carddavMultistatus is the model of the WebDAV multistatus response of a
+// CardDAV addressbook-query, as defined in RFC 6352.
+type carddavMultistatus struct {
+	Responses []struct {
+		AddressData []string `xml:"propstat>prop>address-data"`
+	} `xml:"response"`
+}
+
+const carddavQuery = `<?xml version="1.0" encoding="utf-8"?>
+<C:addressbook-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:carddav">
+  <D:prop>
+    <C:address-data>
+      <C:prop name="FN"/>
+      <C:prop name="N"/>
+    </C:address-data>
+  </D:prop>
+</C:addressbook-query>`
+
+// fetchCardDAV fetches all contacts from a CardDAV address book.
+func fetchCardDAV(url string) ([]vCard, error) {
+	client, err := newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("REPORT", url, strings.NewReader(carddavQuery))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "1")
+	req.Header.Set("User-Agent", userAgent())
+	if contactsFlags.username != "" {
+		req.SetBasicAuth(contactsFlags.username, os.Getenv("NAMNSDAG_CARDDAV_PASSWORD"))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("unexpected status code: %s", resp.Status)
+	}
+	var ms carddavMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	var cards []vCard
+	for _, r := range ms.Responses {
+		for _, data := range r.AddressData {
+			c, err := parseVCards(strings.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			cards = append(cards, c...)
+		}
+	}
+	return cards, nil
+}
+
+func init() {
+	rootCmd.AddCommand(contactsCmd)
+	contactsCmd.AddCommand(contactsImportCmd)
+	contactsCmd.AddCommand(contactsListCmd)
+	contactsImportCmd.Flags().StringVar(&contactsFlags.carddav, "carddav", "", "URL of a CardDAV address book to import contacts from.")
+	contactsImportCmd.Flags().StringVar(&contactsFlags.username, "username", "", "Username for the CardDAV address book.")
+}
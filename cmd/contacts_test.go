@@ -0,0 +1,72 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseVCards(t *testing.T) {
+	tests := []struct {
+		name string
+		vcf  string
+		want []vCard
+	}{
+		{
+			name: "vCard 4.0",
+			vcf:  "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Gösta Berg\r\nN:Berg;Gösta;Henrik;;\r\nEND:VCARD\r\n",
+			want: []vCard{{FullName: "Gösta Berg", GivenNames: []string{"Gösta", "Henrik"}}},
+		},
+		{
+			name: "folded line",
+			vcf:  "BEGIN:VCARD\nFN:Gösta\n  Berg\nEND:VCARD\n",
+			want: []vCard{{FullName: "Gösta Berg"}},
+		},
+		{
+			name: "quoted-printable",
+			vcf: "BEGIN:VCARD\nVERSION:2.1\n" +
+				"FN;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:G=C3=B6sta =C3=85berg\n" +
+				"N;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:=C3=85berg;G=C3=B6sta;;;\n" +
+				"END:VCARD\n",
+			want: []vCard{{FullName: "Gösta Åberg", GivenNames: []string{"Gösta"}}},
+		},
+		{
+			name: "quoted-printable soft line break",
+			vcf: "BEGIN:VCARD\nVERSION:2.1\n" +
+				"FN;QUOTED-PRINTABLE:G=C3=B6s=\nta =C3=85b=\nerg\n" +
+				"END:VCARD\n",
+			want: []vCard{{FullName: "Gösta Åberg"}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseVCards(strings.NewReader(tc.vcf))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+var countdownCmd = &cobra.Command{
+	Use:   "countdown",
+	Short: "Show the next namnsdag of everyone on the watch list",
+	Long: `Show the next namnsdag of everyone on the watch list, sorted by soonest.
+
+Names are added to the watch list using "namnsdag contacts import".`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, err := loadWatchList()
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			writeColored(colorNameNone.Sprint("The watch list is empty"))
+			return nil
+		}
+		cache, err := loadOrFetchNames(now())
+		if err != nil {
+			return err
+		}
+		today := namnsdag.StartOfDay(now())
+		type countdown struct {
+			who  string
+			dom  namnsdag.DoM
+			days int
+		}
+		var countdowns []countdown
+		var missing []string
+		for _, e := range list {
+			who := e.Name
+			if e.Contact != "" {
+				who = e.Contact
+			}
+			names := filterNames(findName(cache, e.Name))
+			if len(names) == 0 {
+				missing = append(missing, who)
+				continue
+			}
+			next := countdown{who: who, days: -1}
+			for _, name := range names {
+				if days := daysUntil(name.DoM(), today); next.days == -1 || days < next.days {
+					next.dom = name.DoM()
+					next.days = days
+				}
+			}
+			countdowns = append(countdowns, next)
+		}
+		sort.SliceStable(countdowns, func(i, j int) bool {
+			return countdowns[i].days < countdowns[j].days
+		})
+
+		defer startPager()()
+		for _, c := range countdowns {
+			writeColored(fmt.Sprintf("%s: namnsdag %s", colorNameOfficial.Sprint(c.who), formatDaysUntil(c.dom, c.days)))
+		}
+		for _, who := range missing {
+			writeColored(fmt.Sprintf("%s: %s", colorNameOfficial.Sprint(who), colorNameNone.Sprint("no namnsdag")))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(countdownCmd)
+}
@@ -0,0 +1,80 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+var datasetFlags = struct {
+	version string
+}{}
+
+var datasetCmd = &cobra.Command{
+	Use:   "dataset",
+	Short: "Manage dataset snapshots",
+}
+
+var datasetCreateCmd = &cobra.Command{
+	Use:   "create <file>",
+	Short: "Write all names to a dataset snapshot file",
+	Long: `Write all names to a dataset snapshot file.
+
+The dataset can be published as a release artifact, and then be used via the
+--dataset flag, or be used directly via the --source flag.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := loadOrFetchNames(now())
+		if err != nil {
+			return err
+		}
+		dataset := namnsdag.Dataset{
+			Version: datasetFlags.version,
+			Names:   cache.Names(),
+		}
+		file, err := os.Create(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		enc := json.NewEncoder(file)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(dataset); err != nil {
+			return fmt.Errorf("write dataset: %w", err)
+		}
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("write dataset: %w", err)
+		}
+		writeColored(fmt.Sprintf("Wrote %d names to %s", len(dataset.Names), args[0]))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(datasetCmd)
+	datasetCmd.AddCommand(datasetCreateCmd)
+	datasetCreateCmd.Flags().StringVar(&datasetFlags.version, "version", "", `Version of the dataset, eg. "v2024".`)
+}
@@ -0,0 +1,123 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+var debugProfileParseFlags = struct {
+	count      int
+	cpuProfile string
+	memProfile string
+}{}
+
+var debugCmd = &cobra.Command{
+	Use:    "debug",
+	Short:  "Tools for debugging namnsdag itself",
+	Hidden: true,
+}
+
+var debugProfileParseCmd = &cobra.Command{
+	Use:   "profile-parse <file.html>",
+	Short: "Measure the time and allocations of parsing a saved page",
+	Long: `Measure the time and allocations of parsing a saved page, such as one saved
+using --record-fixtures, by parsing it repeatedly.
+
+Use --cpuprofile and --memprofile to write pprof profiles, which can be
+inspected using "go tool pprof".`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count := debugProfileParseFlags.count
+		if count < 1 {
+			return errors.New("--count must be at least 1")
+		}
+		page, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		names, err := namnsdag.ParseHTML(bytes.NewReader(page))
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", args[0], err)
+		}
+		writeColored(fmt.Sprintf("Parsed %s from %s (%d bytes)", pluralize(len(names), "name"), args[0], len(page)))
+
+		if path := debugProfileParseFlags.cpuProfile; path != "" {
+			f, err := os.Create(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if err := pprof.StartCPUProfile(f); err != nil {
+				return fmt.Errorf("start CPU profile: %w", err)
+			}
+			defer pprof.StopCPUProfile()
+		}
+
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		for i := 0; i < count; i++ {
+			if _, err := namnsdag.ParseHTML(bytes.NewReader(page)); err != nil {
+				return err
+			}
+		}
+		elapsed := time.Since(start)
+		runtime.ReadMemStats(&after)
+
+		n := uint64(count)
+		writeColored(fmt.Sprintf("%s: %s/op, %d B/op, %d allocs/op",
+			pluralize(count, "iteration"),
+			elapsed/time.Duration(count),
+			(after.TotalAlloc-before.TotalAlloc)/n,
+			(after.Mallocs-before.Mallocs)/n))
+
+		if path := debugProfileParseFlags.memProfile; path != "" {
+			f, err := os.Create(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if err := pprof.Lookup("allocs").WriteTo(f, 0); err != nil {
+				return fmt.Errorf("write memory profile: %w", err)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(debugCmd)
+	debugCmd.AddCommand(debugProfileParseCmd)
+	debugProfileParseCmd.Flags().IntVar(&debugProfileParseFlags.count, "count", 100, "Number of times to parse the page.")
+	debugProfileParseCmd.Flags().StringVar(&debugProfileParseFlags.cpuProfile, "cpuprofile", "", "Write a CPU profile to the given file.")
+	debugProfileParseCmd.Flags().StringVar(&debugProfileParseFlags.memProfile, "memprofile", "", "Write a memory allocation profile to the given file.")
+}
@@ -0,0 +1,68 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Decorations of the text output, toggled using --decorations.
+const (
+	decorationPrefix = "prefix" // the "===" prefix on each line
+	decorationMarker = "marker" // the "*" marker after unofficial names
+	decorationEmoji  = "emoji"  // emoji before the names, such as 🎉
+	decorationNone   = "none"
+)
+
+var defaultDecorations = []string{decorationPrefix, decorationMarker}
+
+// decorations holds which decorations to show in the text output, parsed
+// from the --decorations flag or the decorations config entry.
+var decorations = struct {
+	prefix bool
+	marker bool
+	emoji  bool
+}{
+	prefix: true,
+	marker: true,
+}
+
+func parseDecorations(list []string) error {
+	decorations.prefix = false
+	decorations.marker = false
+	decorations.emoji = false
+	for _, d := range list {
+		switch strings.TrimSpace(d) {
+		case decorationPrefix:
+			decorations.prefix = true
+		case decorationMarker:
+			decorations.marker = true
+		case decorationEmoji:
+			decorations.emoji = true
+		case decorationNone:
+		default:
+			return fmt.Errorf("unknown decoration %q, must be one of: %s, %s, %s, %s",
+				d, decorationPrefix, decorationMarker, decorationEmoji, decorationNone)
+		}
+	}
+	return nil
+}
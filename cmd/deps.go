@@ -0,0 +1,114 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// stdout and stderr are where the commands write their output. They are set
+// from [cobra.Command.OutOrStdout] and [cobra.Command.ErrOrStderr] before
+// running a command, so that the output can be captured, such as in tests.
+var (
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+)
+
+// deps are the dependencies of the commands that can be replaced, such as in
+// tests, by passing options to [NewRootCmd]. Unset fields keep their
+// defaults.
+type deps struct {
+	// clock replaces the system clock, such as to check date-boundary
+	// behavior like midnight rollovers.
+	clock namnsdag.Clock
+	// source replaces the source of names from the --dataset and --source
+	// flags, such as to use names from memory instead of fetching them.
+	source namnsdag.Source
+	// store replaces the cache file, keeping the cached names only in
+	// memory.
+	store *namnsdag.Store
+}
+
+// injected are the dependencies passed to [NewRootCmd].
+var injected deps
+
+// Option replaces a dependency of the commands returned by [NewRootCmd].
+type Option func(*deps)
+
+// WithClock makes the commands tell the current time using the clock instead
+// of the system clock, such as [namnsdag.FixedClock] to check date-boundary
+// behavior like midnight rollovers.
+func WithClock(c namnsdag.Clock) Option {
+	return func(d *deps) { d.clock = c }
+}
+
+// WithSource makes the commands fetch names from the source instead of the
+// one from the --dataset and --source flags.
+func WithSource(s namnsdag.Source) Option {
+	return func(d *deps) { d.source = s }
+}
+
+// WithStore makes the commands keep the cached names in the store instead of
+// in the cache file.
+func WithStore(s *namnsdag.Store) Option {
+	return func(d *deps) { d.store = s }
+}
+
+// setupDeps sets the output writers and the clock of the command.
+func setupDeps(cmd *cobra.Command) {
+	stdout = cmd.OutOrStdout()
+	stderr = cmd.ErrOrStderr()
+	clock = namnsdag.SystemClock
+	if injected.clock != nil {
+		clock = injected.clock
+	}
+}
+
+// isTerminal reports whether the writer is a terminal, such as when stdout
+// is not redirected to a file or pipe.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// exitError makes [Execute] exit with the given exit code, after writing the
+// error, if any.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit code %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e exitError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,88 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare the cached names with the names upstream",
+	Long: `Compare the cached names with the names upstream.
+
+Fetches the names without updating the cache, and prints which names have been
+added, removed, or moved to another day compared to the cached names.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := loadCache()
+		if err != nil {
+			return fmt.Errorf("load cached names: %w", err)
+		}
+		req, err := newRequest("")
+		if err != nil {
+			return err
+		}
+		source, err := nameSource()
+		if err != nil {
+			return err
+		}
+		resp, err := source.Fetch(req)
+		if err != nil {
+			return fmt.Errorf("fetch names: %w", err)
+		}
+		diff := namnsdag.DiffNames(cache.Names(), resp.Names)
+		if diff.IsEmpty() {
+			writeColored("No differences found")
+			return nil
+		}
+		defer startPager()()
+		for _, name := range diff.Added {
+			writeDiffLine(colorDiffAdded, "+", name.DoM().String(), name)
+		}
+		for _, name := range diff.Removed {
+			writeDiffLine(colorDiffRemoved, "-", name.DoM().String(), name)
+		}
+		for _, moved := range diff.Moved {
+			writeDiffLine(colorDiffMoved, "~", fmt.Sprintf("%s -> %s", moved.From.DoM(), moved.To.DoM()), moved.To)
+		}
+		return nil
+	},
+}
+
+func writeDiffLine(c *color.Color, symbol, days string, name namnsdag.Name) {
+	var sb strings.Builder
+	c.Fprint(&sb, symbol)
+	sb.WriteByte(' ')
+	colorPrefix.Fprint(&sb, days)
+	sb.WriteByte(' ')
+	writeName(&sb, name)
+	fmt.Fprintln(stdout, sb.String())
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,143 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+type discordNotifyConfig struct {
+	WebhookURL string `yaml:"webhook-url"`
+}
+
+var notifyDiscordFlags = discordNotifyConfig{}
+
+var notifyDiscordCmd = &cobra.Command{
+	Use:   "discord",
+	Short: "Post today's names to a Discord channel",
+	Long: `Post today's names to a Discord channel, using a webhook.
+
+The names are posted as an embed, with the official and the unofficial names
+as separate fields, and a color that changes with the day of the year. See
+https://support.discord.com/hc/en-us/articles/228383668 for how to create a
+webhook.`,
+	Example: `  namnsdag notify discord --webhook-url https://discord.com/api/webhooks/...`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		webhookURL := cfg.Notify.Discord.WebhookURL
+		if cmd.Flags().Changed("webhook-url") {
+			webhookURL = notifyDiscordFlags.WebhookURL
+		}
+		if webhookURL == "" {
+			return errors.New("missing webhook URL, set using --webhook-url")
+		}
+		l, err := lookupSentenceLang(rootFlags.lang)
+		if err != nil {
+			return err
+		}
+		return runNotify(cmd, "discord "+webhookURL, func(store *namnsdag.Store, day time.Time) error {
+			if err := postJSON(webhookURL, newDiscordMessage(l, storeNames(store, day), day)); err != nil {
+				return fmt.Errorf("post to Discord: %w", err)
+			}
+			writeColored(fmt.Sprintf("Posted names of %s to Discord", day.Format(time.DateOnly)))
+			return nil
+		})
+	},
+}
+
+// discordMessage is the model of a Discord webhook message.
+// See https://discord.com/developers/docs/resources/webhook#execute-webhook
+type discordMessage struct {
+	Username string         `json:"username"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+func newDiscordMessage(l sentenceLang, names []namnsdag.Name, day time.Time) discordMessage {
+	embed := discordEmbed{
+		Title: l.titleOf(day),
+		Color: dayColor(day),
+	}
+	official, unofficial := splitOfficial(names)
+	if len(names) == 0 {
+		embed.Description = l.sentence(names, day, now())
+	}
+	if len(official) > 0 {
+		embed.Fields = append(embed.Fields, discordField{Name: l.official, Value: joinTitles(official), Inline: true})
+	}
+	if len(unofficial) > 0 {
+		embed.Fields = append(embed.Fields, discordField{Name: l.unofficial, Value: joinTitles(unofficial), Inline: true})
+	}
+	return discordMessage{
+		Username: "namnsdag",
+		Embeds:   []discordEmbed{embed},
+	}
+}
+
+// dayColor returns a color as 0xRRGGBB, whose hue goes around the color wheel
+// once per year, so that each day gets its own color.
+func dayColor(day time.Time) int {
+	const saturation, value = 0.6, 0.9
+	hue := float64(day.YearDay()-1) / 366 * 6
+	chroma := value * saturation
+	x := chroma * (1 - math.Abs(math.Mod(hue, 2)-1))
+	var r, g, b float64
+	switch int(hue) {
+	case 0:
+		r, g = chroma, x
+	case 1:
+		r, g = x, chroma
+	case 2:
+		g, b = chroma, x
+	case 3:
+		g, b = x, chroma
+	case 4:
+		r, b = x, chroma
+	default:
+		r, b = chroma, x
+	}
+	m := value - chroma
+	toByte := func(f float64) int { return int(math.Round((f + m) * 255)) }
+	return toByte(r)<<16 | toByte(g)<<8 | toByte(b)
+}
+
+func init() {
+	notifyCmd.AddCommand(notifyDiscordCmd)
+	notifyDiscordCmd.Flags().StringVar(&notifyDiscordFlags.WebhookURL, "webhook-url", "", "URL of the Discord webhook.")
+}
@@ -0,0 +1,69 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+var docsCmd = &cobra.Command{
+	Use:    "docs",
+	Short:  "Generate reference documentation",
+	Hidden: true,
+}
+
+var docsManCmd = &cobra.Command{
+	Use:   "man <dir>",
+	Short: "Generate man pages for all commands into a directory",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := os.MkdirAll(args[0], 0755); err != nil {
+			return err
+		}
+		header := &doc.GenManHeader{
+			Title:   "NAMNSDAG",
+			Section: "1",
+			Source:  "namnsdag " + version(),
+		}
+		return doc.GenManTree(rootCmd, header, args[0])
+	},
+}
+
+var docsMarkdownCmd = &cobra.Command{
+	Use:   "markdown <dir>",
+	Short: "Generate Markdown reference docs for all commands into a directory",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := os.MkdirAll(args[0], 0755); err != nil {
+			return err
+		}
+		return doc.GenMarkdownTree(rootCmd, args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+	docsCmd.AddCommand(docsManCmd)
+	docsCmd.AddCommand(docsMarkdownCmd)
+}
@@ -0,0 +1,284 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	_ "embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+// Digests of the "namnsdag notify email" command.
+const (
+	digestDaily  = "daily"
+	digestWeekly = "weekly"
+)
+
+type emailNotifyConfig struct {
+	To     []string   `yaml:"to"`
+	From   string     `yaml:"from"`
+	Digest string     `yaml:"digest"`
+	SMTP   smtpConfig `yaml:"smtp"`
+}
+
+// smtpConfig contains the settings of the SMTP server to send emails with.
+type smtpConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+var notifyEmailFlags = emailNotifyConfig{}
+
+var notifyEmailCmd = &cobra.Command{
+	Use:   "email",
+	Short: "Send a digest of the upcoming names as an email",
+	Long: `Send a digest of the upcoming names as an email.
+
+The daily digest contains today's names, and the weekly digest contains the
+names of the coming 7 days. When using --at, the weekly digest is only sent on
+Mondays.
+
+The email is sent using the SMTP server from the "notify.email.smtp" section of
+the config file. Port 465 uses implicit TLS, while other ports use STARTTLS
+when supported by the server.`,
+	Example: `  namnsdag notify email --to me@example.com --digest weekly`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings := emailNotifySettings(cmd)
+		if len(settings.To) == 0 {
+			return errors.New("missing recipients, set using --to")
+		}
+		if settings.SMTP.Host == "" {
+			return errors.New(`missing SMTP server, set "notify.email.smtp.host" in the config file`)
+		}
+		if settings.From == "" {
+			settings.From = settings.SMTP.Username
+		}
+		days := 1
+		switch settings.Digest {
+		case digestDaily:
+		case digestWeekly:
+			days = 7
+		default:
+			return fmt.Errorf("unknown digest %q, must be one of: %s, %s", settings.Digest, digestDaily, digestWeekly)
+		}
+		l, err := lookupSentenceLang(rootFlags.lang)
+		if err != nil {
+			return err
+		}
+		scheduled := notifyAt(cmd) != ""
+		key := fmt.Sprintf("email %s %s", settings.Digest, strings.Join(settings.To, ","))
+		return runNotify(cmd, key, func(store *namnsdag.Store, day time.Time) error {
+			if scheduled && days == 7 && day.Weekday() != time.Monday {
+				return nil
+			}
+			msg, err := newEmailDigest(settings, l, store, day, days)
+			if err != nil {
+				return err
+			}
+			if err := sendEmail(settings, msg); err != nil {
+				return fmt.Errorf("send email: %w", err)
+			}
+			writeColored(fmt.Sprintf("Sent %s digest to %s", settings.Digest, strings.Join(settings.To, ", ")))
+			return nil
+		})
+	},
+}
+
+// emailNotifySettings returns the settings of the email notifier, where the
+// flags take precedence over the config file.
+func emailNotifySettings(cmd *cobra.Command) emailNotifyConfig {
+	settings := cfg.Notify.Email
+	flags := cmd.Flags()
+	if flags.Changed("to") {
+		settings.To = notifyEmailFlags.To
+	}
+	if flags.Changed("from") {
+		settings.From = notifyEmailFlags.From
+	}
+	if flags.Changed("digest") || settings.Digest == "" {
+		settings.Digest = notifyEmailFlags.Digest
+	}
+	return settings
+}
+
+var (
+	//go:embed templates/email.txt
+	emailText string
+	//go:embed templates/email.html
+	emailHTML string
+
+	emailTextTemplate = template.Must(template.New("email.txt").Parse(emailText))
+	emailHTMLTemplate = htmltemplate.Must(htmltemplate.New("email.html").Parse(emailHTML))
+)
+
+// emailDigest is the data of the email templates.
+type emailDigest struct {
+	Lang           string
+	Title          string
+	Days           []emailDay
+	HasUnofficial  bool
+	UnofficialNote string
+}
+
+type emailDay struct {
+	Date  string
+	Names []namnsdag.Name
+}
+
+// newEmailDigest renders a digest of the names of the given number of days,
+// starting at day, as a MIME message with both a plain text and a HTML body.
+func newEmailDigest(settings emailNotifyConfig, l sentenceLang, store *namnsdag.Store, day time.Time, days int) ([]byte, error) {
+	digest := emailDigest{
+		Lang:           rootFlags.lang,
+		Title:          l.titleOf(day),
+		UnofficialNote: l.unofficial,
+	}
+	if days > 1 {
+		last := day.AddDate(0, 0, days-1)
+		digest.Title = l.title(l.date(day.Day(), l.months[day.Month()-1]) + " – " + l.date(last.Day(), l.months[last.Month()-1]))
+	}
+	for i, upcoming := range store.Upcoming(day, days) {
+		d := day.AddDate(0, 0, i)
+		names := filterNames(upcoming.Names)
+		for _, name := range names {
+			digest.HasUnofficial = digest.HasUnofficial || name.TypeOfName.IsUnofficial()
+		}
+		digest.Days = append(digest.Days, emailDay{
+			Date:  l.weekdays[d.Weekday()] + " " + l.date(d.Day(), l.months[d.Month()-1]),
+			Names: names,
+		})
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "From: %s\r\n", settings.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(settings.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", digest.Title))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+	if err := writeEmailPart(mw, "text/plain", func(w io.Writer) error {
+		return emailTextTemplate.Execute(w, digest)
+	}); err != nil {
+		return nil, err
+	}
+	if err := writeEmailPart(mw, "text/html", func(w io.Writer) error {
+		return emailHTMLTemplate.Execute(w, digest)
+	}); err != nil {
+		return nil, err
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeEmailPart(mw *multipart.Writer, contentType string, render func(w io.Writer) error) error {
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if err := render(qp); err != nil {
+		return fmt.Errorf("render %s: %w", contentType, err)
+	}
+	return qp.Close()
+}
+
+// sendEmail sends the message using the SMTP server, with implicit TLS on
+// port 465, and otherwise STARTTLS if supported by the server.
+func sendEmail(settings emailNotifyConfig, msg []byte) error {
+	port := settings.SMTP.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(settings.SMTP.Host, strconv.Itoa(port))
+	var auth smtp.Auth
+	if settings.SMTP.Username != "" {
+		auth = smtp.PlainAuth("", settings.SMTP.Username, settings.SMTP.Password, settings.SMTP.Host)
+	}
+	if port != 465 {
+		return smtp.SendMail(addr, auth, settings.From, settings.To, msg)
+	}
+	conn, err := tls.Dial("tcp", addr, &tls.Config{ServerName: settings.SMTP.Host})
+	if err != nil {
+		return err
+	}
+	c, err := smtp.NewClient(conn, settings.SMTP.Host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(settings.From); err != nil {
+		return err
+	}
+	for _, to := range settings.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func init() {
+	notifyCmd.AddCommand(notifyEmailCmd)
+	notifyEmailCmd.Flags().StringSliceVar(&notifyEmailFlags.To, "to", nil, "Email addresses to send the digest to.")
+	notifyEmailCmd.Flags().StringVar(&notifyEmailFlags.From, "from", "", "Email address to send the digest from (default the SMTP username).")
+	notifyEmailCmd.Flags().StringVar(&notifyEmailFlags.Digest, "digest", digestDaily, "Digest to send, one of: daily, weekly.")
+}
@@ -0,0 +1,91 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var fetchFlags = struct {
+	writeToday string
+}{}
+
+var fetchCmd = &cobra.Command{
+	Use:   "fetch",
+	Short: "Fetch the names into the cache, if outdated",
+	Long: `Fetch the names into the cache, if outdated, without printing them.
+
+Meant to be run periodically, eg. from cron or a systemd timer. Use
+--write-today to also write today's names as a single line of plain text to a
+file, which trivial consumers, such as the MOTD, conky, or scripts, can cat:
+
+  namnsdag fetch --write-today /run/user/1000/namnsdag-today.txt`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		day := now()
+		cache, err := loadOrFetchNames(day)
+		if err != nil {
+			return err
+		}
+		if fetchFlags.writeToday == "" {
+			return nil
+		}
+		line := statusLine(namesForToday(cache, day), defaultSeparator, 0)
+		if err := writeFileAtomic(fetchFlags.writeToday, []byte(line+"\n")); err != nil {
+			return fmt.Errorf("write today's names: %w", err)
+		}
+		return nil
+	},
+}
+
+// writeFileAtomic writes to a temporary file first, and then renames it, so
+// that concurrent readers never see a partially written file.
+func writeFileAtomic(path string, b []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	if _, err := file.Write(b); err != nil {
+		return err
+	}
+	if err := file.Chmod(0644); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func init() {
+	rootCmd.AddCommand(fetchCmd)
+	fetchCmd.Flags().StringVar(&fetchFlags.writeToday, "write-today", "", "Also write today's names as a single line of plain text to the given file.")
+}
@@ -0,0 +1,130 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+// greetTemplates are the built-in templates for "namnsdag greet", selected
+// by name using --template.
+var greetTemplates = map[string]string{
+	"default": "Grattis på namnsdagen, {{.Name}}! 🎉",
+	"formal":  "Varmt grattis på namnsdagen, {{.Name}}! Hoppas att du får en fin dag.",
+	"short":   "Grattis {{.Name}}! 🎉",
+	"english": "Happy name day, {{.Name}}! 🎉",
+}
+
+// greetData is the data passed to the greeting templates.
+type greetData struct {
+	// Name is the name to greet, with the casing from the cached names if
+	// found, or else as given.
+	Name string
+	// Date is the namnsdag of the name, such as "May 18", or empty if the name
+	// has no namnsdag.
+	Date string
+}
+
+var greetFlags = struct {
+	template string
+	copy     bool
+}{}
+
+var greetCmd = &cobra.Command{
+	Use:   "greet <name>",
+	Short: "Print a congratulation message for someone's namnsdag",
+	Long: `Print a congratulation message for someone's namnsdag.
+
+The message is printed as-is without any decorations, making it suitable for
+piping into other programs, such as messaging CLIs.
+
+The message is rendered from a Go text/template, which is either the name of
+a built-in template or the template itself, given using --template or the
+greet-template config entry. The template has access to the fields {{.Name}}
+and {{.Date}}.
+
+Built-in templates:
+` + formatGreetTemplates(),
+	Example: `  namnsdag greet Erik
+  namnsdag greet Erik --template formal
+  namnsdag greet Erik --copy
+  namnsdag greet Erik --template 'Grattis {{.Name}}, det är din dag den {{.Date}}!'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		text := greetFlags.template
+		if !cmd.Flags().Changed("template") && cfg.GreetTemplate != "" {
+			text = cfg.GreetTemplate
+		}
+		if builtin, ok := greetTemplates[text]; ok {
+			text = builtin
+		}
+		tmpl, err := template.New("greet").Parse(text)
+		if err != nil {
+			return fmt.Errorf("parse greet template: %w", err)
+		}
+
+		data := greetData{Name: strings.TrimSpace(args[0])}
+		cache, err := loadOrFetchNames(now())
+		if err != nil {
+			writeWarning(err)
+		}
+		if names := findName(cache, args[0]); len(names) > 0 {
+			data.Name = names[0].Name
+			data.Date = formatDoM(names[0].DoM())
+		}
+
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, data); err != nil {
+			return fmt.Errorf("execute greet template: %w", err)
+		}
+		message := strings.TrimRight(sb.String(), "\n")
+		fmt.Fprintln(stdout, message)
+		if greetFlags.copy {
+			return copyToClipboard(message)
+		}
+		return nil
+	},
+}
+
+func formatGreetTemplates() string {
+	keys := make([]string, 0, len(greetTemplates))
+	for key := range greetTemplates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&sb, "  %-8s %s\n", key, greetTemplates[key])
+	}
+	return sb.String()
+}
+
+func init() {
+	rootCmd.AddCommand(greetCmd)
+
+	greetCmd.Flags().StringVarP(&greetFlags.template, "template", "t", "default", "Built-in template name, or a Go text/template of the message")
+	greetCmd.Flags().BoolVar(&greetFlags.copy, "copy", false, "Also copy the message to the system clipboard, eg. for pasting into a chat.")
+}
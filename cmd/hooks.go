@@ -0,0 +1,86 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+)
+
+// hooksConfig is the model of the shell commands in the config file that are
+// run before and after fetching names.
+type hooksConfig struct {
+	BeforeFetch string `yaml:"before-fetch"`
+	AfterFetch  string `yaml:"after-fetch"`
+}
+
+// runBeforeFetchHook runs the configured before-fetch hook, if any. The fetch
+// should be aborted if the hook fails.
+func runBeforeFetchHook() error {
+	if cfg.Hooks.BeforeFetch == "" {
+		return nil
+	}
+	if err := runHook(cfg.Hooks.BeforeFetch, nil); err != nil {
+		return fmt.Errorf("before-fetch hook: %w", err)
+	}
+	return nil
+}
+
+// runAfterFetchHook runs the configured after-fetch hook, if any, with the
+// fetched names as a JSON array on its stdin.
+func runAfterFetchHook(names []namnsdag.Name) error {
+	if cfg.Hooks.AfterFetch == "" {
+		return nil
+	}
+	b, err := json.Marshal(names)
+	if err != nil {
+		return fmt.Errorf("after-fetch hook: encode names: %w", err)
+	}
+	if err := runHook(cfg.Hooks.AfterFetch, bytes.NewReader(b)); err != nil {
+		return fmt.Errorf("after-fetch hook: %w", err)
+	}
+	return nil
+}
+
+// runHook runs a shell command. Its output is written to stderr, so it is
+// not mixed up with the names written to stdout.
+func runHook(command string, stdin io.Reader) error {
+	c := shellCommand(command)
+	c.Stdin = stdin
+	c.Stdout = stderr
+	c.Stderr = stderr
+	return c.Run()
+}
+
+// shellCommand creates a command that runs the command line using the
+// system's shell.
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
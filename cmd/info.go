@@ -0,0 +1,161 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+// nameStatsMaxAge is how old the cached name statistics may be before they
+// are refetched. SCB only publishes new statistics once per year.
+const nameStatsMaxAge = 30 * 24 * time.Hour
+
+var infoCmd = &cobra.Command{
+	Use:   "info <name>",
+	Short: "Show information about a name, such as its namnsdag and popularity",
+	Long: `Show information about a name, such as its namnsdag and popularity.
+
+The popularity is from the name statistics of Statistics Sweden (SCB), showing
+how many people in Sweden carry the name as their first name. The statistics
+are cached separately from the names, and the cached statistics are used if
+they cannot be fetched, such as when offline.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := loadOrFetchNames(now())
+		if err != nil {
+			if cache.NamesPerDay == nil {
+				return err
+			}
+			writeWarning(err)
+		}
+		displayName := strings.TrimSpace(args[0])
+		names := findName(cache, args[0])
+		names = filterNames(names)
+		if len(names) > 0 {
+			displayName = names[0].Name
+			var days []string
+			for _, name := range names {
+				days = append(days, formatDoM(name.DoM()))
+			}
+			writeColored(fmt.Sprintf("%s: namnsdag on %s", colorNameOfficial.Sprint(displayName), strings.Join(days, ", ")))
+			if variants := names[0].Variants; len(variants) > 0 {
+				writeColored(fmt.Sprintf("Spelling variants: %s", strings.Join(variants, ", ")))
+			}
+			if link, err := names[0].CanonicalURL(); err == nil {
+				writeColored(fmt.Sprintf("Read more: %s", link))
+			}
+		} else {
+			writeColored(fmt.Sprintf("%s: %s", colorNameOfficial.Sprint(displayName), colorNameNone.Sprint("no namnsdag")))
+		}
+
+		stats, err := loadNameStats(args[0])
+		if errors.Is(err, namnsdag.ErrNameStatsNotFound) {
+			writeColored("Popularity: carried by too few people to be in the statistics")
+			return nil
+		}
+		if err != nil {
+			writeWarning(fmt.Errorf("name statistics: %w", err))
+			return nil
+		}
+		writeColored(fmt.Sprintf("Popularity: carried by %s people as first name, rank %d (SCB, %d)",
+			formatThousands(stats.Count), stats.Rank, stats.Year))
+		return nil
+	},
+}
+
+// loadNameStats looks up the statistics of a name in the cached statistics,
+// which are refetched if outdated. If fetching fails, then any outdated
+// cached statistics are used instead.
+func loadNameStats(name string) (namnsdag.NameStats, error) {
+	path, err := namnsdag.NameStatsCacheFile()
+	if err != nil {
+		return namnsdag.NameStats{}, err
+	}
+	var table namnsdag.NameStatsTable
+	if !rootFlags.noCache {
+		table, err = namnsdag.LoadNameStatsTable(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) && !errors.Is(err, namnsdag.ErrCacheCorrupt) {
+			return namnsdag.NameStats{}, fmt.Errorf("load cached statistics: %w", err)
+		}
+	}
+	if now().Sub(table.FetchedAt) > nameStatsMaxAge && !rootFlags.noFetch {
+		fetched, err := fetchNameStats()
+		switch {
+		case err == nil:
+			table = fetched
+			if !rootFlags.noCache {
+				if err := namnsdag.SaveNameStatsTable(path, table); err != nil {
+					return namnsdag.NameStats{}, fmt.Errorf("cache statistics: %w", err)
+				}
+			}
+		case len(table.Names) > 0:
+			writeWarning(fmt.Errorf("%w, using cached statistics from %s",
+				err, inLocation(table.FetchedAt).Format(time.DateOnly)))
+		default:
+			return namnsdag.NameStats{}, err
+		}
+	}
+	if len(table.Names) == 0 {
+		return namnsdag.NameStats{}, errors.New("no cached statistics, and skipping fetch because --no-fetch was supplied")
+	}
+	return table.Lookup(name)
+}
+
+func fetchNameStats() (namnsdag.NameStatsTable, error) {
+	req, err := newRequest("")
+	if err != nil {
+		return namnsdag.NameStatsTable{}, err
+	}
+	colorStatus.Fprintf(stderr, "Fetching name statistics from %s... ", namnsdag.SCBNameStatsURL)
+	table, err := namnsdag.FetchNameStatsTable(req)
+	if err != nil {
+		colorError.Fprintln(stderr, "error")
+		return table, fmt.Errorf("fetch statistics: %w", err)
+	}
+	colorStatus.Fprintf(stderr, "fetched %d names\n", len(table.Names))
+	return table, nil
+}
+
+// formatThousands formats a number with comma as thousands separator, such
+// as "12,345".
+func formatThousands(n int) string {
+	s := strconv.Itoa(n)
+	var sb strings.Builder
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+}
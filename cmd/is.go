@@ -0,0 +1,94 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+var isFlags = struct {
+	print bool
+}{}
+
+var isCmd = &cobra.Command{
+	Use:   "is <name> [YYYY-MM-DD]",
+	Short: "Check if a name is celebrated on a given day",
+	Long: `Check if a name is celebrated on a given day, defaulting to today.
+
+Exits with exit code 0 if the name is celebrated, 1 if not, and 2 on errors,
+without printing anything unless --print is set. This makes it suitable for
+shell conditionals:
+
+  if namnsdag is Erik; then echo "Grattis Erik!"; fi`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		celebrated, err := isCelebrated(args)
+		if err != nil {
+			return exitError{code: 2, err: err}
+		}
+		if !celebrated {
+			return exitError{code: 1}
+		}
+		return nil
+	},
+}
+
+func isCelebrated(args []string) (bool, error) {
+	day := now()
+	if len(args) == 2 {
+		var err error
+		day, err = parseDate(args[1])
+		if err != nil {
+			return false, fmt.Errorf("parse argument: %w", err)
+		}
+	}
+	cache, err := loadOrFetchNames(day)
+	if err != nil {
+		if cache.NamesPerDay == nil {
+			return false, err
+		}
+		if isFlags.print {
+			writeWarning(err)
+		}
+	}
+	dom := namnsdag.NewDoMFromTime(day)
+	for _, name := range filterNames(findName(cache, args[0])) {
+		if name.DoM() == dom {
+			if isFlags.print {
+				writeColored(fmt.Sprintf("Yes, %s is celebrated on %s", colorNameOfficial.Sprint(name.Name), formatDoM(dom)))
+			}
+			return true, nil
+		}
+	}
+	if isFlags.print {
+		writeColored(fmt.Sprintf("No, %s is not celebrated on %s", colorNameOfficial.Sprint(args[0]), formatDoM(dom)))
+	}
+	return false, nil
+}
+
+func init() {
+	rootCmd.AddCommand(isCmd)
+
+	isCmd.Flags().BoolVarP(&isFlags.print, "print", "p", false, "Print the answer, instead of only setting the exit code.")
+}
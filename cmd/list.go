@@ -0,0 +1,83 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+var listFlags = struct {
+	months monthFilter
+}{}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the names of every day, one day per line",
+	Long: `List the names of every day, one day per line, on the format
+"06-06 Gustav, Gösta".
+
+Lists all days of the year, or only the days of one month using --month, or
+of a range of months using --from-month and --to-month. This is easier to grep
+than the calendar from "namnsdag month".`,
+	Example: `  namnsdag list --month 6
+  namnsdag list --from-month 6 --to-month 8
+  namnsdag list | grep -i erik`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := listFlags.months.validate(); err != nil {
+			return err
+		}
+		cache, err := loadOrFetchNames(now())
+		if err != nil {
+			if cache.NamesPerDay == nil {
+				return err
+			}
+			writeWarning(err)
+		}
+		defer startPager()()
+		for _, dom := range namnsdag.AllDoMs() {
+			if !listFlags.months.includes(dom.Month) {
+				continue
+			}
+			var sb strings.Builder
+			colorPrefix.Fprint(&sb, dom)
+			for i, name := range filterNames(cache.NamesPerDay[dom]) {
+				if i > 0 {
+					colorNameDelimiter.Fprint(&sb, ",")
+				}
+				sb.WriteByte(' ')
+				writeName(&sb, name)
+			}
+			fmt.Fprintln(stdout, sb.String())
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	addMonthFilterFlags(listCmd, &listFlags.months)
+}
@@ -0,0 +1,227 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// matrixSyncTimeout is how long the Matrix homeserver may hold a sync
+// request while waiting for new events.
+const matrixSyncTimeout = 30 * time.Second
+
+// matrixClient is a minimal client of the Matrix client-server API, with
+// only what is needed by the Matrix bot.
+// See https://spec.matrix.org/latest/client-server-api/
+type matrixClient struct {
+	homeserver  string
+	accessToken string
+	userID      string
+	client      *http.Client
+	txnPrefix   string
+	// txnCount is incremented for each message sent, and may be used
+	// concurrently, such as when replying while posting the daily names.
+	txnCount atomic.Int64
+}
+
+func newMatrixClient(homeserver string, client *http.Client) *matrixClient {
+	return &matrixClient{
+		homeserver: strings.TrimSuffix(homeserver, "/"),
+		client:     client,
+		txnPrefix:  strconv.FormatInt(time.Now().UnixNano(), 36),
+	}
+}
+
+// matrixError is the error response of the Matrix API.
+type matrixError struct {
+	Code    string `json:"errcode"`
+	Message string `json:"error"`
+}
+
+func (e matrixError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// do sends a request to the Matrix API, with the body and response encoded
+// as JSON. Both body and result may be nil.
+func (c *matrixClient) do(method, path string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, c.homeserver+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent())
+	if c.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var merr matrixError
+		if err := json.NewDecoder(resp.Body).Decode(&merr); err == nil && merr.Code != "" {
+			return merr
+		}
+		return fmt.Errorf("non-2xx status code: %s", resp.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// login logs in using a password, and keeps the access token.
+func (c *matrixClient) login(user, password string) error {
+	body := map[string]any{
+		"type": "m.login.password",
+		"identifier": map[string]string{
+			"type": "m.id.user",
+			"user": user,
+		},
+		"password":                    password,
+		"initial_device_display_name": "namnsdag",
+	}
+	var result struct {
+		AccessToken string `json:"access_token"`
+		UserID      string `json:"user_id"`
+	}
+	if err := c.do(http.MethodPost, "/_matrix/client/v3/login", body, &result); err != nil {
+		return err
+	}
+	c.accessToken = result.AccessToken
+	c.userID = result.UserID
+	return nil
+}
+
+// whoami looks up the user ID of the access token.
+func (c *matrixClient) whoami() error {
+	var result struct {
+		UserID string `json:"user_id"`
+	}
+	if err := c.do(http.MethodGet, "/_matrix/client/v3/account/whoami", nil, &result); err != nil {
+		return err
+	}
+	c.userID = result.UserID
+	return nil
+}
+
+// join joins a room by its ID or alias, such as "#family:example.com", and
+// returns the room's ID.
+func (c *matrixClient) join(room string) (string, error) {
+	var result struct {
+		RoomID string `json:"room_id"`
+	}
+	if err := c.do(http.MethodPost, "/_matrix/client/v3/join/"+url.PathEscape(room), struct{}{}, &result); err != nil {
+		return "", err
+	}
+	return result.RoomID, nil
+}
+
+// send posts a plain text message to a room.
+func (c *matrixClient) send(roomID, text string) error {
+	txnID := fmt.Sprintf("%s-%d", c.txnPrefix, c.txnCount.Add(1))
+	path := fmt.Sprintf("/_matrix/client/v3/rooms/%s/send/m.room.message/%s", url.PathEscape(roomID), txnID)
+	body := map[string]string{
+		"msgtype": "m.notice",
+		"body":    text,
+	}
+	return c.do(http.MethodPut, path, body, nil)
+}
+
+// matrixSync is the subset of the sync response used by the bot.
+type matrixSync struct {
+	NextBatch string `json:"next_batch"`
+	Rooms     struct {
+		Join map[string]struct {
+			Timeline struct {
+				Events []matrixEvent `json:"events"`
+			} `json:"timeline"`
+		} `json:"join"`
+	} `json:"rooms"`
+}
+
+type matrixEvent struct {
+	Type    string `json:"type"`
+	Sender  string `json:"sender"`
+	Content struct {
+		MsgType string `json:"msgtype"`
+		Body    string `json:"body"`
+	} `json:"content"`
+}
+
+// sync waits for new events since the given batch token, or returns only the
+// batch token of the current state if since is empty.
+func (c *matrixClient) sync(since string) (matrixSync, error) {
+	query := url.Values{}
+	if since == "" {
+		query.Set("filter", `{"room":{"timeline":{"limit":0}}}`)
+	} else {
+		query.Set("since", since)
+		query.Set("timeout", strconv.FormatInt(matrixSyncTimeout.Milliseconds(), 10))
+	}
+	var result matrixSync
+	err := c.do(http.MethodGet, "/_matrix/client/v3/sync?"+query.Encode(), nil, &result)
+	return result, err
+}
+
+// listen calls handle for every new text message sent by other users in the
+// joined rooms, and only returns if the access token is rejected. Other
+// errors are written as warnings, and the sync is retried.
+func (c *matrixClient) listen(handle func(roomID, body string)) error {
+	var since string
+	for {
+		resp, err := c.sync(since)
+		if merr, ok := err.(matrixError); ok && merr.Code == "M_UNKNOWN_TOKEN" {
+			return err
+		} else if err != nil {
+			writeWarning(fmt.Errorf("matrix sync: %w", err))
+			time.Sleep(matrixSyncTimeout)
+			continue
+		}
+		for roomID, room := range resp.Rooms.Join {
+			for _, event := range room.Timeline.Events {
+				if event.Type == "m.room.message" && event.Content.MsgType == "m.text" && event.Sender != c.userID {
+					handle(roomID, event.Content.Body)
+				}
+			}
+		}
+		since = resp.NextBatch
+	}
+}
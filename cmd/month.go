@@ -0,0 +1,182 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var colorToday = themeColor("today", color.ReverseVideo)
+
+var monthCmd = &cobra.Command{
+	Use:   "month [YYYY-MM]",
+	Short: "Show a calendar of a month with its namnsdagar",
+	Long: `Show a calendar of a month with its namnsdagar, defaulting to the current
+month.
+
+The month and weekday names are localized using --lang, and the first day of
+the week defaults to Monday for Swedish and Sunday for English, which can be
+changed using the first-weekday config entry.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		today := now()
+		year, month := today.Year(), today.Month()
+		if len(args) == 1 {
+			t, err := time.ParseInLocation("2006-01", args[0], location)
+			if err != nil {
+				return fmt.Errorf("parse argument: %w", err)
+			}
+			year, month = t.Year(), t.Month()
+		}
+		lang, err := lookupSentenceLang(rootFlags.lang)
+		if err != nil {
+			return err
+		}
+		first, err := firstWeekday()
+		if err != nil {
+			return err
+		}
+		cache, err := loadOrFetchNames(today)
+		if err != nil {
+			if cache.NamesPerDay == nil {
+				return err
+			}
+			writeWarning(err)
+		}
+		m := newCalendarMonth(cache, year, month, lang)
+
+		defer startPager()()
+		writeMonthGrid(m, year, lang, first, today)
+		fmt.Fprintln(stdout)
+		for _, day := range m.Days {
+			if len(day.Names) == 0 && len(day.Holidays) == 0 {
+				continue
+			}
+			var sb strings.Builder
+			dayColor := colorPrefix
+			if day.RedDay {
+				dayColor = colorHoliday
+			}
+			dayColor.Fprintf(&sb, "%2d %-4s", day.Date.Day(), day.Weekday)
+			sb.WriteByte(' ')
+			for i, name := range day.Names {
+				if i > 0 {
+					colorNameDelimiter.Fprint(&sb, ", ")
+				}
+				writeName(&sb, name)
+			}
+			if len(day.Holidays) > 0 {
+				if len(day.Names) > 0 {
+					sb.WriteByte(' ')
+				}
+				colorHoliday.Fprintf(&sb, "(%s)", day.HolidayNames())
+			}
+			fmt.Fprintln(stdout, sb.String())
+		}
+		return nil
+	},
+}
+
+// writeMonthGrid writes the days of the month as a grid of weeks, like the
+// "cal" command, starting each week on the given weekday.
+func writeMonthGrid(m calendarMonth, year int, lang sentenceLang, first time.Weekday, today time.Time) {
+	const cellWidth = 3
+	var weekColumn string
+	if rootFlags.weekNumbers {
+		weekColumn = strings.Repeat(" ", cellWidth)
+	}
+	title := fmt.Sprintf("%s %d", m.Title, year)
+	gridWidth := 7*cellWidth - 1
+	padding := (gridWidth - utf8.RuneCountInString(title)) / 2
+	colorText.Println(weekColumn + strings.Repeat(" ", max(padding, 0)) + title)
+
+	var header []string
+	for i := 0; i < 7; i++ {
+		header = append(header, capitalize(truncateRunes(lang.weekdays[(int(first)+i)%7], 2)))
+	}
+	colorPrefix.Println(weekColumn + strings.Join(header, " "))
+
+	offset := (int(m.Days[0].Date.Weekday()) - int(first) + 7) % 7
+	for start := 0; start < len(m.Days); {
+		end := min(start+7-offset, len(m.Days))
+		var sb strings.Builder
+		if rootFlags.weekNumbers {
+			// ISO weeks start on Mondays, so with weeks starting on Sundays,
+			// the week number is taken from the last day of the row.
+			colorPrefix.Fprintf(&sb, "%2d ", m.Days[end-1].Week)
+		}
+		sb.WriteString(strings.Repeat(" ", offset*cellWidth))
+		for i, day := range m.Days[start:end] {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			cell := fmt.Sprintf("%2d", day.Date.Day())
+			switch {
+			case sameDate(day.Date, today):
+				colorToday.Fprint(&sb, cell)
+			case day.RedDay:
+				colorHoliday.Fprint(&sb, cell)
+			default:
+				sb.WriteString(cell)
+			}
+		}
+		fmt.Fprintln(stdout, sb.String())
+		start, offset = end, 0
+	}
+}
+
+// firstWeekday returns the first day of the week from the first-weekday
+// config entry, defaulting to Monday, or Sunday for English.
+func firstWeekday() (time.Weekday, error) {
+	switch strings.ToLower(cfg.FirstWeekday) {
+	case "":
+		if rootFlags.lang == langEnglish {
+			return time.Sunday, nil
+		}
+		return time.Monday, nil
+	case "monday":
+		return time.Monday, nil
+	case "sunday":
+		return time.Sunday, nil
+	default:
+		return 0, fmt.Errorf("invalid first-weekday %q, must be one of: monday, sunday", cfg.FirstWeekday)
+	}
+}
+
+func truncateRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
+
+func init() {
+	rootCmd.AddCommand(monthCmd)
+}
@@ -0,0 +1,88 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+// monthFilter is the model of the --month, --from-month, and --to-month
+// flags, used to only include the names of some months.
+type monthFilter struct {
+	month     int
+	fromMonth int
+	toMonth   int
+}
+
+func addMonthFilterFlags(cmd *cobra.Command, f *monthFilter) {
+	cmd.Flags().IntVarP(&f.month, "month", "m", 0, "Only include the given month, 1-12.")
+	cmd.Flags().IntVar(&f.fromMonth, "from-month", 0, "Only include the months from the given month, 1-12, such as 6 for the summer months together with --to-month 8. Wraps around the new year if after --to-month.")
+	cmd.Flags().IntVar(&f.toMonth, "to-month", 0, "Only include the months up to and including the given month, 1-12.")
+	cmd.MarkFlagsMutuallyExclusive("month", "from-month")
+	cmd.MarkFlagsMutuallyExclusive("month", "to-month")
+}
+
+func (f monthFilter) validate() error {
+	for _, flag := range []struct {
+		name  string
+		value int
+	}{{"month", f.month}, {"from-month", f.fromMonth}, {"to-month", f.toMonth}} {
+		if flag.value < 0 || flag.value > 12 {
+			return fmt.Errorf("invalid --%s %d, must be between 1 and 12", flag.name, flag.value)
+		}
+	}
+	return nil
+}
+
+// includes reports whether the month passes the filter. A range where
+// --from-month is after --to-month wraps around the new year, so that
+// 11 to 2 includes November, December, January, and February.
+func (f monthFilter) includes(month time.Month) bool {
+	m := int(month)
+	if f.month != 0 {
+		return m == f.month
+	}
+	from, to := f.fromMonth, f.toMonth
+	if from == 0 {
+		from = 1
+	}
+	if to == 0 {
+		to = 12
+	}
+	if from <= to {
+		return m >= from && m <= to
+	}
+	return m >= from || m <= to
+}
+
+func (f monthFilter) filter(names []namnsdag.Name) []namnsdag.Name {
+	var filtered []namnsdag.Name
+	for _, name := range names {
+		if f.includes(name.Month) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,148 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+var motdFlags = struct {
+	ascii bool
+	color bool
+}{}
+
+var motdCmd = &cobra.Command{
+	Use:   "motd",
+	Short: "Print a short banner with today's date and names for the MOTD",
+	Long: `Print a short banner with today's date, week number, and names, meant for
+the message of the day (MOTD), such as from a script in /etc/update-motd.d/:
+
+  #!/bin/sh
+  namnsdag motd --color --no-fetch
+
+Use --ascii for terminals or log viewers that only support ASCII, which draws
+the border using "+-|" and removes any diacritics, such as "Gosta" instead of
+"Gösta". Use --color to keep the colors even though the MOTD scripts are not
+run in a terminal.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if motdFlags.color {
+			color.NoColor = false
+		}
+		day := now()
+		cache, err := loadOrFetchNames(day)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(stdout, motdBanner(namesForToday(cache, day), day, motdFlags.ascii))
+		return nil
+	},
+}
+
+// motdLine is a line of the banner, both as plain text, used to measure
+// its width, and with colors.
+type motdLine struct {
+	plain   string
+	colored string
+}
+
+// motdBanner returns the date and names inside a border. With ascii, the
+// banner only contains ASCII characters.
+func motdBanner(names []namnsdag.Name, day time.Time, ascii bool) string {
+	_, week := day.ISOWeek()
+	date := fmt.Sprintf("%s, week %d", day.Format("Monday 2 January 2006"), week)
+	lines := []motdLine{{date, colorText.Sprint(date)}}
+	if len(names) == 0 {
+		lines = append(lines, motdLine{"No names today", colorNameNone.Sprint("No names today")})
+	} else {
+		var plain, colored strings.Builder
+		plain.WriteString("Names: ")
+		colorText.Fprint(&colored, "Names: ")
+		for i, name := range names {
+			if i > 0 {
+				plain.WriteString(", ")
+				colorNameDelimiter.Fprint(&colored, ", ")
+			}
+			plain.WriteString(name.Name)
+			if name.TypeOfName.IsUnofficial() && decorations.marker {
+				plain.WriteByte('*')
+			}
+			plain.WriteString(genderSymbol(name))
+			writeName(&colored, name)
+		}
+		lines = append(lines, motdLine{plain.String(), colored.String()})
+	}
+	if ascii {
+		for i, line := range lines {
+			lines[i] = motdLine{toASCII(line.plain), toASCII(line.colored)}
+		}
+	}
+
+	corners, horizontal, vertical := [4]string{"╭", "╮", "╰", "╯"}, "─", "│"
+	if ascii {
+		corners, horizontal, vertical = [4]string{"+", "+", "+", "+"}, "-", "|"
+	}
+	var width int
+	for _, line := range lines {
+		width = max(width, utf8.RuneCountInString(line.plain))
+	}
+	border := strings.Repeat(horizontal, width+2)
+	var sb strings.Builder
+	colorPrefix.Fprint(&sb, corners[0]+border+corners[1])
+	sb.WriteByte('\n')
+	for _, line := range lines {
+		padding := strings.Repeat(" ", width-utf8.RuneCountInString(line.plain))
+		fmt.Fprintf(&sb, "%s %s%s %s\n", colorPrefix.Sprint(vertical), line.colored, padding, colorPrefix.Sprint(vertical))
+	}
+	colorPrefix.Fprint(&sb, corners[2]+border+corners[3])
+	sb.WriteByte('\n')
+	return sb.String()
+}
+
+// toASCII removes the diacritics, such as "ö" to "o", and replaces any
+// remaining non-ASCII characters with "?".
+func toASCII(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	s, _, _ = transform.String(t, s)
+	return strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII {
+			return '?'
+		}
+		return r
+	}, s)
+}
+
+func init() {
+	rootCmd.AddCommand(motdCmd)
+	motdCmd.Flags().BoolVar(&motdFlags.ascii, "ascii", false, "Only use ASCII characters, for terminals without Unicode support.")
+	motdCmd.Flags().BoolVar(&motdFlags.color, "color", false, "Always use colors, even when not writing to a terminal.")
+}
@@ -0,0 +1,80 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+)
+
+// Values of the names-filter config entry, deciding which kinds of names to
+// show by default.
+const (
+	// namesFilterOfficialOnly only shows the official names.
+	namesFilterOfficialOnly = "official-only"
+	// namesFilterIncludeNew shows the official and newly added names, as well
+	// as names of any unknown type, but not the unofficial names. Same as
+	// using --no-unofficial.
+	namesFilterIncludeNew = "include-new"
+	// namesFilterIncludeUnofficial shows all names, including the unofficial
+	// names, aka "Bolibompa namnsdagar". Same as using --unofficial.
+	namesFilterIncludeUnofficial = "include-unofficial"
+)
+
+// namesFilter returns which kinds of names to show, from the --unofficial
+// and --no-unofficial flags, or else the names-filter config entry.
+func namesFilter() (string, error) {
+	switch {
+	case rootFlags.unofficial:
+		return namesFilterIncludeUnofficial, nil
+	case rootFlags.noUnofficial:
+		return namesFilterIncludeNew, nil
+	}
+	switch cfg.NamesFilter {
+	case "":
+		return namesFilterIncludeUnofficial, nil
+	case namesFilterOfficialOnly, namesFilterIncludeNew, namesFilterIncludeUnofficial:
+		return cfg.NamesFilter, nil
+	default:
+		return "", fmt.Errorf("invalid names-filter %q, must be one of: %s, %s, %s", cfg.NamesFilter,
+			namesFilterOfficialOnly, namesFilterIncludeNew, namesFilterIncludeUnofficial)
+	}
+}
+
+// filterNames returns only the kinds of names to show, as decided by
+// [namesFilter].
+func filterNames(names []namnsdag.Name) []namnsdag.Name {
+	filter, err := namesFilter()
+	if err != nil || filter == namesFilterIncludeUnofficial {
+		return names
+	}
+	var filtered []namnsdag.Name
+	for _, name := range names {
+		switch {
+		case name.TypeOfName.IsOfficial():
+			filtered = append(filtered, name)
+		case filter == namesFilterIncludeNew && !name.TypeOfName.IsUnofficial():
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,198 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+// notifyConfig contains the settings of the notifiers in the config file.
+type notifyConfig struct {
+	At      string              `yaml:"at"`
+	Slack   slackNotifyConfig   `yaml:"slack"`
+	Discord discordNotifyConfig `yaml:"discord"`
+	Email   emailNotifyConfig   `yaml:"email"`
+	SMS     smsNotifyConfig     `yaml:"sms"`
+}
+
+var notifyFlags = struct {
+	at    string
+	force bool
+}{}
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Send today's names as a notification, such as to a chat",
+	Long: `Send today's names as a notification, such as to a chat.
+
+By default, the notification is sent once. Use --at to instead keep running
+and send the notification every day at the given time, in the time zone from
+--timezone.
+
+Each notification is only sent once per day and recipient, so that retries,
+such as from cron, or multiple machines sharing the same state directory,
+don't send duplicates. Use --force to send it again anyway.`,
+}
+
+// notifyAt returns the time of day to send the notifications at, where the
+// --at flag takes precedence over the config file, or empty if they should
+// only be sent once.
+func notifyAt(cmd *cobra.Command) string {
+	if cmd.Flags().Changed("at") {
+		return notifyFlags.at
+	}
+	return cfg.Notify.At
+}
+
+// runNotify calls notify with today's date, or every day at --at if set.
+// The key identifies the notifier and its recipients, such as the webhook
+// URL, and is used to skip notifications already sent that day.
+func runNotify(cmd *cobra.Command, key string, notify func(store *namnsdag.Store, day time.Time) error) error {
+	at := notifyAt(cmd)
+	var tod timeOfDay
+	if at != "" {
+		var err error
+		if tod, err = parseTimeOfDay(at); err != nil {
+			return err
+		}
+	}
+	store, err := loadStore(cmd.Context())
+	if err != nil {
+		return err
+	}
+	send := func(day time.Time) error {
+		if !notifyFlags.force && alreadyNotified(key, day) {
+			colorStatus.Fprintf(stderr, "Already notified about %s, skipping (use --force to notify anyway)\n", day.Format(time.DateOnly))
+			return nil
+		}
+		if err := notify(store, day); err != nil {
+			return err
+		}
+		if err := markNotified(key, day); err != nil {
+			writeWarning(fmt.Errorf("save notification state: %w", err))
+		}
+		return nil
+	}
+	if at == "" {
+		return send(now())
+	}
+	runDaily(tod, send)
+	return nil
+}
+
+// notifiedKey hashes the key of a notification, so that secrets, such as
+// webhook URLs, are not stored in plain text in the state file.
+func notifiedKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
+
+// alreadyNotified reports whether the notification was already sent for the
+// given day, according to the state file.
+func alreadyNotified(key string, day time.Time) bool {
+	notified := map[string]string{}
+	if err := loadState(stateNotified, &notified); err != nil {
+		writeWarning(err)
+		return false
+	}
+	return notified[notifiedKey(key)] == day.Format(time.DateOnly)
+}
+
+// markNotified records in the state file that the notification was sent for
+// the given day.
+func markNotified(key string, day time.Time) error {
+	notified := map[string]string{}
+	if err := loadState(stateNotified, &notified); err != nil {
+		return err
+	}
+	notified[notifiedKey(key)] = day.Format(time.DateOnly)
+	return saveState(stateNotified, notified)
+}
+
+// splitOfficial splits the names into the official names, including new
+// names, and the unofficial names.
+func splitOfficial(names []namnsdag.Name) (official, unofficial []namnsdag.Name) {
+	for _, name := range names {
+		if name.TypeOfName.IsUnofficial() {
+			unofficial = append(unofficial, name)
+		} else {
+			official = append(official, name)
+		}
+	}
+	return official, unofficial
+}
+
+// joinTitles joins the names as eg. "Gustav, Gösta", without any markers.
+func joinTitles(names []namnsdag.Name) string {
+	return strings.Join(nameTitles(names), ", ")
+}
+
+// nameTitles returns the names' titles, such as "Gösta".
+func nameTitles(names []namnsdag.Name) []string {
+	titles := make([]string, len(names))
+	for i, name := range names {
+		titles[i] = name.Name
+	}
+	return titles
+}
+
+// postJSON posts the body encoded as JSON, such as to a webhook.
+func postJSON(url string, body any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	client, err := newHTTPClient()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent())
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("non-2xx status code: %s", resp.Status)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+	notifyCmd.PersistentFlags().BoolVar(&notifyFlags.force, "force", false, "Send the notification even if it was already sent today.")
+	notifyCmd.PersistentFlags().StringVar(&notifyFlags.at, "at", "", "Keep running, and send the notification every day at the given time of day, on the format HH:MM.")
+}
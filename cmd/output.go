@@ -0,0 +1,239 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+)
+
+// Built-in output formats. Any other format is looked up as an output plugin.
+const (
+	outputText     = "text"
+	outputJSON     = "json"
+	outputMarkdown = "markdown"
+	outputHTML     = "html"
+	outputSentence = "sentence"
+	outputI3blocks = "i3blocks"
+	outputConky    = "conky"
+	outputPSObject = "psobject"
+)
+
+// outputPluginPrefix is the prefix of the executables on the PATH that are
+// used as output plugins. For example "--output myformat" will run the
+// executable "namnsdag-output-myformat".
+const outputPluginPrefix = "namnsdag-output-"
+
+// outputPluginName is the allowed names of output plugins, so that the
+// --output flag cannot be used to run executables by path, such as
+// "--output ../../bin/sh".
+var outputPluginName = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// namesResult is the model of the names for a given day, as written by the
+// structured output formats and piped to output plugins.
+type namesResult struct {
+	Date      string             `json:"date"`
+	Week      int                `json:"week,omitempty"`
+	Names     []namnsdag.Name    `json:"names"`
+	Holidays  []namnsdag.Holiday `json:"holidays,omitempty"`
+	ThemeDays []namnsdag.Name    `json:"themeDays,omitempty"`
+	Watched   []watchEntry       `json:"watched,omitempty"`
+	Meta      resultMeta         `json:"meta"`
+}
+
+// resultMeta is the provenance of the names in a [namesResult], so that
+// consumers can detect stale names.
+type resultMeta struct {
+	// Source is where the names were fetched from.
+	Source string `json:"source,omitempty"`
+	// UpdatedAt is when the names were fetched, or nil if never.
+	UpdatedAt *time.Time `json:"updatedAt"`
+	// ETag is the HTTP ETag of the fetched names, if any.
+	ETag string `json:"etag,omitempty"`
+	// Version is the version of namnsdag that wrote the result.
+	Version string `json:"version"`
+}
+
+func newNamesResult(cache namnsdag.Cache, day time.Time) namesResult {
+	info := cache.DayInfo(namnsdag.NewDoMFromTime(day))
+	return newDayResult(namesForToday(cache, day), info, cache.ETag, day)
+}
+
+// newDayResult creates the result of the already filtered names of a day.
+func newDayResult(names []namnsdag.Name, info namnsdag.DayInfo, etag string, day time.Time) namesResult {
+	if names == nil {
+		names = []namnsdag.Name{}
+	}
+	result := namesResult{
+		Date:  day.Format(time.DateOnly),
+		Names: names,
+		Meta: resultMeta{
+			Source:  info.Source,
+			ETag:    etag,
+			Version: version(),
+		},
+	}
+	if !info.UpdatedAt.IsZero() {
+		result.Meta.UpdatedAt = &info.UpdatedAt
+	}
+	if rootFlags.weekNumbers {
+		_, result.Week = day.ISOWeek()
+	}
+	if rootFlags.holidays {
+		result.Holidays = namnsdag.HolidaysOn(day)
+	}
+	if rootFlags.themeDays {
+		result.ThemeDays = themeDaysOn(day)
+	}
+	result.Watched = watchedNames(names)
+	return result
+}
+
+// writeOutput writes the names for a given day in the format given by the
+// --output flag.
+func writeOutput(cache namnsdag.Cache, day time.Time) error {
+	names := namesForToday(cache, day)
+	if rootFlags.print0 {
+		return writeNamesNul(names)
+	}
+	switch rootFlags.output {
+	case outputText:
+		writeNames(names, day)
+		writeWatched(watchedNames(names))
+		if rootFlags.holidays {
+			writeHolidays(namnsdag.HolidaysOn(day))
+		}
+		if rootFlags.themeDays {
+			writeThemeDays(themeDaysOn(day))
+		}
+		return nil
+	case outputJSON:
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(newNamesResult(cache, day))
+	case outputMarkdown:
+		return writeMarkdown(stdout, names, day)
+	case outputHTML:
+		return writeHTML(stdout, names, day)
+	case outputSentence:
+		return writeSentence(stdout, names, day)
+	case outputI3blocks, outputConky:
+		return writeStatusLine(stdout, names)
+	case outputPSObject:
+		return writePSObject(stdout, names, day)
+	default:
+		return runOutputPlugin(rootFlags.output, newNamesResult(cache, day))
+	}
+}
+
+// writeMarkdown writes the names as a Markdown bullet list with a date
+// heading, suitable for pasting into wikis, issues, and chat tools.
+func writeMarkdown(w io.Writer, names []namnsdag.Name, day time.Time) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Namnsdagar %s", day.Format(time.DateOnly))
+	if rootFlags.weekNumbers {
+		_, week := day.ISOWeek()
+		fmt.Fprintf(&sb, " (week %d)", week)
+	}
+	sb.WriteString("\n\n")
+	if len(names) == 0 {
+		sb.WriteString("_No names found for this day._\n")
+	}
+	var hasUnofficial bool
+	for _, name := range names {
+		sb.WriteString("- " + markdownEscaper.Replace(name.Name))
+		if name.TypeOfName.IsUnofficial() {
+			sb.WriteString("[^unofficial]")
+			hasUnofficial = true
+		}
+		sb.WriteByte('\n')
+	}
+	if hasUnofficial {
+		sb.WriteString("\n[^unofficial]: Unofficial namnsdag, aka \"Bolibompa namnsdag\".\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeHTML writes the names as a small HTML fragment, meant to be embedded
+// into other pages and styled using the classes, where each name has a class
+// for its type, such as "namnsdag-name--unofficial".
+func writeHTML(w io.Writer, names []namnsdag.Name, day time.Time) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<div class=\"namnsdag\" data-date=\"%s\">\n", day.Format(time.DateOnly))
+	if len(names) == 0 {
+		sb.WriteString("  <p class=\"namnsdag-none\">No names found for this day.</p>\n")
+	} else {
+		sb.WriteString("  <ul class=\"namnsdag-names\">\n")
+		for _, name := range names {
+			typeClass := strings.ReplaceAll(strings.ToLower(string(name.TypeOfName)), "_", "-")
+			title := html.EscapeString(name.Name)
+			if link, err := name.CanonicalURL(); err == nil {
+				title = fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(link), title)
+			}
+			fmt.Fprintf(&sb, "    <li class=\"namnsdag-name namnsdag-name--%s\">%s</li>\n",
+				html.EscapeString(typeClass), title)
+		}
+		sb.WriteString("  </ul>\n")
+	}
+	sb.WriteString("</div>\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "`", "\\`", "<", `\<`)
+
+// runOutputPlugin pipes the result as JSON to the output plugin's stdin, and
+// lets it write directly to stdout and stderr.
+func runOutputPlugin(format string, result namesResult) error {
+	if !outputPluginName.MatchString(format) {
+		return fmt.Errorf("unknown output format %q: must only contain lowercase letters, digits, and dashes", format)
+	}
+	path, err := exec.LookPath(outputPluginPrefix + format)
+	if errors.Is(err, exec.ErrNotFound) {
+		return fmt.Errorf("unknown output format %q: no %q found in PATH", format, outputPluginPrefix+format)
+	} else if err != nil {
+		return fmt.Errorf("output plugin %q: %w", format, err)
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("output plugin %q: encode names: %w", format, err)
+	}
+	c := exec.Command(path)
+	c.Stdin = bytes.NewReader(b)
+	c.Stdout = stdout
+	c.Stderr = stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("output plugin %q: %w", format, err)
+	}
+	return nil
+}
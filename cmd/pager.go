@@ -0,0 +1,74 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+)
+
+// defaultPager is used when $PAGER is unset. Same as git, less is told to
+// quit if the output fits on one screen, and to keep the colors.
+const (
+	defaultPager     = "less"
+	defaultLessFlags = "FRX"
+)
+
+// startPager pipes stdout through $PAGER, like git does, if stdout is a
+// terminal and --no-pager is not set. The returned function closes the pipe
+// and waits for the pager to exit, and must always be called.
+func startPager() (stop func()) {
+	noop := func() {}
+	if rootFlags.noPager || !isTerminal(stdout) {
+		return noop
+	}
+	pager, ok := os.LookupEnv("PAGER")
+	if !ok {
+		pager = defaultPager
+	}
+	if pager == "" || pager == "cat" {
+		return noop
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return noop
+	}
+	c := shellCommand(pager)
+	c.Stdin = r
+	c.Stdout = stdout
+	c.Stderr = stderr
+	c.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		c.Env = append(c.Env, "LESS="+defaultLessFlags)
+	}
+	if err := c.Start(); err != nil {
+		r.Close()
+		w.Close()
+		return noop
+	}
+	r.Close()
+	out := stdout
+	stdout = w
+	return func() {
+		stdout = out
+		w.Close()
+		c.Wait()
+	}
+}
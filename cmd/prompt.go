@@ -0,0 +1,53 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultPromptWidth is the default maximum width of the --prompt output,
+// used unless --max-width is set.
+const defaultPromptWidth = 30
+
+// writePrompt writes the names of the given day as a short line without
+// colors, for embedding in shell prompts such as Starship. It only reads the
+// cache and never fetches, and writes nothing instead of failing, so a
+// missing or outdated cache never slows down or breaks the prompt.
+func writePrompt(day time.Time) {
+	if rootFlags.noCache {
+		return
+	}
+	cache, err := loadCache()
+	if err != nil {
+		return
+	}
+	names := namesForToday(cache, day)
+	if len(names) == 0 {
+		return
+	}
+	width := rootFlags.maxWidth
+	if width <= 0 {
+		width = defaultPromptWidth
+	}
+	fmt.Fprintln(stdout, statusLine(names, rootFlags.separator, width))
+}
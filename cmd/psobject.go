@@ -0,0 +1,99 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf16"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+)
+
+// psObject is the model of a name in the psobject output format, with flat
+// PascalCase properties as is the convention in PowerShell.
+type psObject struct {
+	Date     string
+	Name     string
+	Type     string
+	Official bool
+}
+
+// writePSObject writes the names as a JSON array of flat objects, meant for
+// PowerShell's ConvertFrom-Json. The array is written even if there is only
+// one name or none, and all non-ASCII characters are escaped, as Windows
+// PowerShell decodes the output of native commands using the console's code
+// page, which would garble names such as "Åsa".
+func writePSObject(w io.Writer, names []namnsdag.Name, day time.Time) error {
+	objects := make([]psObject, len(names))
+	for i, name := range names {
+		objects[i] = psObject{
+			Date:     day.Format(time.DateOnly),
+			Name:     name.Name,
+			Type:     psTypeName(name.TypeOfName),
+			Official: name.TypeOfName.IsOfficial(),
+		}
+	}
+	b, err := json.MarshalIndent(objects, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(escapeNonASCII(b), '\n'))
+	return err
+}
+
+func psTypeName(t namnsdag.Type) string {
+	switch {
+	case t.IsOfficial():
+		return "Official"
+	case t.IsNew():
+		return "New"
+	case t.IsUnofficial():
+		return "Unofficial"
+	case t.IsThemeDay():
+		return "ThemeDay"
+	default:
+		return string(t)
+	}
+}
+
+// escapeNonASCII replaces all non-ASCII characters in the JSON with \u
+// escape sequences, using surrogate pairs for characters outside the Basic
+// Multilingual Plane. It relies on non-ASCII characters only occurring inside
+// strings in JSON.
+func escapeNonASCII(b []byte) []byte {
+	var buf bytes.Buffer
+	for _, r := range string(b) {
+		switch {
+		case r < 0x80:
+			buf.WriteRune(r)
+		case r > 0xFFFF:
+			r1, r2 := utf16.EncodeRune(r)
+			fmt.Fprintf(&buf, `\u%04x\u%04x`, r1, r2)
+		default:
+			fmt.Fprintf(&buf, `\u%04x`, r)
+		}
+	}
+	return buf.Bytes()
+}
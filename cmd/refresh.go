@@ -0,0 +1,68 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/pflag"
+)
+
+// globalFlags are the flags passed on to the background refresh. Assigned in
+// init, as referring to rootCmd directly would be an initialization cycle.
+var globalFlags *pflag.FlagSet
+
+// startBackgroundRefresh starts "namnsdag fetch" in the background, with the
+// same global flags, to update the cache for next time without waiting for
+// it. Its output is discarded.
+func startBackgroundRefresh() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	args := []string{"fetch"}
+	globalFlags.VisitAll(func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range slice.GetSlice() {
+				args = append(args, fmt.Sprintf("--%s=%s", f.Name, v))
+			}
+			return
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", f.Name, f.Value))
+	})
+	// Must fetch in the foreground of the background process, to not spawn
+	// yet another one
+	args = append(args, "--async-refresh=false")
+	c := exec.Command(exe, args...)
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Process.Release()
+}
+
+func init() {
+	globalFlags = rootCmd.PersistentFlags()
+}
@@ -22,33 +22,82 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/fatih/color"
 	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
-	colorPrefix = color.New(color.FgHiBlack)
-	colorText   = color.New(color.FgYellow)
-	colorStatus = color.New(color.FgHiBlack, color.Italic)
-	colorError  = color.New(color.FgRed)
+	colorPrefix  = themeColor("prefix", color.FgHiBlack)
+	colorText    = themeColor("text", color.FgYellow)
+	colorStatus  = themeColor("status", color.FgHiBlack, color.Italic)
+	colorError   = themeColor("error", color.FgRed)
+	colorWarning = themeColor("warning", color.FgYellow)
 
-	colorNameOfficial         = color.New(color.FgHiCyan)
-	colorNameUnofficial       = color.New(color.FgCyan, color.Italic)
-	colorNameUnofficialSymbol = color.New(color.FgMagenta, color.Italic)
-	colorNameDelimiter        = color.New(color.FgHiBlack)
-	colorNameNone             = color.New(color.FgRed, color.Italic)
+	colorNameOfficial         = themeColor("name-official", color.FgHiCyan)
+	colorNameUnofficial       = themeColor("name-unofficial", color.FgCyan, color.Italic)
+	colorNameUnofficialSymbol = themeColor("name-unofficial-symbol", color.FgMagenta, color.Italic)
+	colorNameGender           = themeColor("name-gender", color.FgHiBlack)
+	colorNameDelimiter        = themeColor("name-delimiter", color.FgHiBlack)
+	colorNameNone             = themeColor("name-none", color.FgRed, color.Italic)
+
+	colorHoliday  = themeColor("holiday", color.FgHiRed)
+	colorThemeDay = themeColor("theme-day", color.FgHiGreen)
+
+	colorDiffAdded   = themeColor("diff-added", color.FgGreen)
+	colorDiffRemoved = themeColor("diff-removed", color.FgRed)
+	colorDiffMoved   = themeColor("diff-moved", color.FgYellow)
 
 	rootFlags = struct {
 		noFetch      bool
 		noCache      bool
+		stateless    bool
 		noUnofficial bool
+		unofficial   bool
+		configFile   string
+		proxy        string
+		output       string
+		showUpdated  bool
+		dataset      string
+		source       string
+		publicKey    string
+
+		recordFixtures string
+		strict         bool
+		force          bool
+		debug          bool
+		light          bool
+		profile        string
+		holidays       bool
+		themeDays      bool
+		lang           string
+		decorations    []string
+		maxWidth       int
+		separator      string
+		truncate       bool
+		noPager        bool
+		print0         bool
+		filter         string
+		weekNumbers    bool
+		timezone       string
+		prompt         bool
+		oncePerDay     bool
+		softFail       bool
+		asyncRefresh   bool
+		verbose        bool
+		showGender     bool
+		copy           bool
 	}{}
 )
 
@@ -61,57 +110,263 @@ var rootCmd = &cobra.Command{
 When run, it will query https://www.dagensnamnsdag.nu/ to obtain today's names,
 and cache the results inside ~/.cache/namnsdag/`,
 	Args: cobra.MaximumNArgs(1),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		setupDeps(cmd)
+		setupConsole()
+		if rootFlags.stateless {
+			// Containers conventionally collect all logs from stdout
+			stderr = stdout
+		}
+		if rootFlags.debug {
+			slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{
+				Level: slog.LevelDebug,
+			})))
+		}
+		if err := loadConfig(cmd); err != nil {
+			return err
+		}
+		if rootFlags.stateless && cfg.CacheDir == "" {
+			// Keep the names only in memory
+			rootFlags.noCache = true
+			namnsdag.ReadOnlyCache = true
+		}
+		if err := loadTimezone(rootFlags.timezone); err != nil {
+			return err
+		}
+		if _, err := namesFilter(); err != nil {
+			return err
+		}
+		return parseDecorations(rootFlags.decorations)
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		if rootFlags.prompt {
+			// Must never delay the shell prompt
+			return
+		}
+		notifyIfUpdateAvailable()
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		day := time.Now()
+		day := now()
 		if len(args) == 1 {
 			var err error
-			day, err = time.Parse(time.DateOnly, args[0])
+			day, err = parseDate(args[0])
 			if err != nil {
 				return fmt.Errorf("parse argument: %w", err)
 			}
 		}
-		namesPerDay, err := loadOrFetchNames()
+		if rootFlags.prompt {
+			writePrompt(day)
+			return nil
+		}
+		if rootFlags.oncePerDay && shownToday() {
+			return nil
+		}
+		cache, err := loadOrFetchNames(day)
+		if err != nil && rootFlags.softFail && len(cache.NamesPerDay) > 0 {
+			slog.Debug("using cached names as fetching failed", "error", err)
+			err = nil
+		}
 		if err != nil {
-			if namesPerDay != nil {
-				colorStatus.Println("Found cached names, but they might be outdated.")
-				writeNames(namesForToday(namesPerDay, day), day)
+			if cache.NamesPerDay != nil {
+				colorStatus.Fprintln(stderr, "Found cached names, but they might be outdated.")
+				if err := writeRootOutput(cache, day); err != nil {
+					writeError(err)
+				}
+			}
+			return err
+		}
+		if rootFlags.copy {
+			stop := recordOutput()
+			err := writeRootOutput(cache, day)
+			text := stop()
+			if err != nil {
+				return err
+			}
+			if err := copyToClipboard(text); err != nil {
+				return err
+			}
+		} else if err := writeRootOutput(cache, day); err != nil {
+			return err
+		}
+		if rootFlags.oncePerDay {
+			if err := markShownToday(); err != nil {
+				writeWarning(fmt.Errorf("save when the names were last shown: %w", err))
 			}
-			writeError(err)
-			os.Exit(1)
-			return nil
 		}
-		writeNames(namesForToday(namesPerDay, day), day)
 		return nil
 	},
 	SilenceErrors: true,
 	SilenceUsage:  true,
 }
 
+// writeRootOutput writes the names for the given day. With --filter, an
+// error is returned if no names matched, for use in scripts.
+func writeRootOutput(cache namnsdag.Cache, day time.Time) error {
+	if err := writeOutput(cache, day); err != nil {
+		return err
+	}
+	if rootFlags.showUpdated {
+		writeUpdated(cache)
+	}
+	if rootFlags.filter != "" && len(namesForToday(cache, day)) == 0 {
+		return fmt.Errorf("no names matching %q on %s", rootFlags.filter, day.Format(time.DateOnly))
+	}
+	return nil
+}
+
+// writeUpdated writes when the cache was last updated, and from where. It is
+// written to stdout for the text output, but to stderr for all other outputs
+// to not interfere with any structured output.
+func writeUpdated(cache namnsdag.Cache) {
+	w := stderr
+	if rootFlags.output == outputText && !rootFlags.print0 {
+		w = stdout
+	}
+	if cache.UpdatedAt.IsZero() {
+		colorStatus.Fprintln(w, "Names have never been updated.")
+		return
+	}
+	source := cache.Source
+	if source == "" {
+		source = "unknown source"
+	}
+	colorStatus.Fprintf(w, "Names updated %s (%s) from %s\n",
+		inLocation(cache.UpdatedAt).Format("2006-01-02 15:04"),
+		formatAge(now().Sub(cache.UpdatedAt)),
+		source)
+}
+
+// formatAge formats a duration as a rough human-readable age, such as
+// "5 minutes ago" or "3 days ago".
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return pluralize(int(d/time.Minute), "minute") + " ago"
+	case d < 24*time.Hour:
+		return pluralize(int(d/time.Hour), "hour") + " ago"
+	default:
+		return pluralize(int(d/(24*time.Hour)), "day") + " ago"
+	}
+}
+
+func pluralize(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
 func writeError(err error) {
-	colorPrefix.Print("Error: ")
-	colorError.Println(err)
+	colorPrefix.Fprint(stderr, "Error: ")
+	colorError.Fprintln(stderr, err)
+}
+
+func writeWarning(err error) {
+	colorPrefix.Fprint(stderr, "Warning: ")
+	colorWarning.Fprintln(stderr, err)
+}
+
+func namesForToday(cache namnsdag.Cache, today time.Time) []namnsdag.Name {
+	return filterDayNames(cache.NamesPerDay[namnsdag.NewDoMFromTime(today)])
 }
 
-func namesForToday(namesPerDay map[namnsdag.DoM][]namnsdag.Name, today time.Time) []namnsdag.Name {
-	dom := namnsdag.NewDoMFromTime(today)
-	names := namesPerDay[dom]
-	if rootFlags.noUnofficial {
-		names = filterOnlyOfficial(names)
+// filterDayNames returns the names of a day that are shown, filtered by the
+// names filter and the --filter flag.
+func filterDayNames(names []namnsdag.Name) []namnsdag.Name {
+	names = filterNames(names)
+	if rootFlags.filter != "" {
+		names = filterNamesContaining(names, rootFlags.filter)
 	}
 	return names
 }
 
+// filterNamesContaining returns only the names that contain the substring,
+// compared using [namnsdag.NormalizeName].
+func filterNamesContaining(names []namnsdag.Name, substr string) []namnsdag.Name {
+	normalized := namnsdag.NormalizeName(substr)
+	var filtered []namnsdag.Name
+	for _, name := range names {
+		if strings.Contains(namnsdag.NormalizeName(name.Name), normalized) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered
+}
+
 func writeNames(names []namnsdag.Name, day time.Time) {
 	prefix := "Today's names"
-	if !sameDate(day, time.Now()) {
+	emoji := "🎉"
+	if !sameDate(day, now()) {
 		prefix = fmt.Sprintf("Names for %s", day.Format(time.DateOnly))
+		emoji = "📅"
+	}
+	if rootFlags.weekNumbers {
+		_, week := day.ISOWeek()
+		prefix += fmt.Sprintf(" (week %d)", week)
+	}
+	if decorations.emoji {
+		prefix = emoji + " " + prefix
 	}
 
 	if len(names) == 0 {
 		writeColored(fmt.Sprintf("%s: %s", prefix, colorNameNone.Sprint("no names found for today")))
 		return
 	}
-	writeColored(fmt.Sprintf("%s: %s", prefix, joinNames(names)))
+	label := prefix + ": "
+	indent := utf8.RuneCountInString(label)
+	if decorations.prefix {
+		indent += len("=== ")
+	}
+	writeColored(label + layoutNames(names, indent, outputWidth(), rootFlags.truncate))
+}
+
+func writeHolidays(holidays []namnsdag.Holiday) {
+	for _, holiday := range holidays {
+		var kinds []string
+		if holiday.RedDay {
+			kinds = append(kinds, "red day")
+		}
+		if holiday.FlagDay {
+			kinds = append(kinds, "flag day")
+		}
+		writeColored(fmt.Sprintf("%s %s",
+			colorHoliday.Sprint(holiday.Name),
+			colorStatus.Sprintf("(%s)", strings.Join(kinds, ", "))))
+	}
+}
+
+func writeWatched(entries []watchEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	var sb strings.Builder
+	for i, e := range entries {
+		if i > 0 {
+			colorNameDelimiter.Fprint(&sb, ", ")
+		}
+		if e.Contact != "" {
+			colorNameOfficial.Fprint(&sb, e.Contact)
+		} else {
+			colorNameOfficial.Fprint(&sb, e.Name)
+		}
+	}
+	writeColored(fmt.Sprintf("Celebrating among your contacts: %s", sb.String()))
+}
+
+func writeThemeDays(themeDays []namnsdag.Name) {
+	if len(themeDays) == 0 {
+		return
+	}
+	var sb strings.Builder
+	for i, themeDay := range themeDays {
+		if i > 0 {
+			colorNameDelimiter.Fprint(&sb, ", ")
+		}
+		colorThemeDay.Fprint(&sb, themeDay.Name)
+	}
+	writeColored(fmt.Sprintf("Theme days: %s", sb.String()))
 }
 
 func sameDate(a, b time.Time) bool {
@@ -122,103 +377,406 @@ func sameDate(a, b time.Time) bool {
 
 func writeColored(text string) {
 	var sb strings.Builder
-	colorPrefix.Fprint(&sb, "===")
-	sb.WriteByte(' ')
+	if decorations.prefix {
+		colorPrefix.Fprint(&sb, "===")
+		sb.WriteByte(' ')
+	}
 	colorText.Fprint(&sb, text)
-	fmt.Println(sb.String())
+	fmt.Fprintln(stdout, sb.String())
 }
 
-func joinNames(names []namnsdag.Name) string {
-	var sb strings.Builder
-	for i, name := range names {
-		if i > 0 {
-			colorNameDelimiter.Fprint(&sb, ", ")
-		}
-		if name.TypeOfName != namnsdag.TypeUnofficial {
-			colorNameOfficial.Fprint(&sb, name.Name)
-		} else {
-			colorNameUnofficial.Fprint(&sb, name.Name)
-			colorNameUnofficialSymbol.Fprint(&sb, "*")
+func writeName(w io.Writer, name namnsdag.Name) {
+	if !name.TypeOfName.IsUnofficial() {
+		colorNameOfficial.Fprint(w, name.Name)
+	} else {
+		colorNameUnofficial.Fprint(w, name.Name)
+		if decorations.marker {
+			colorNameUnofficialSymbol.Fprint(w, "*")
 		}
 	}
-	return sb.String()
+	if symbol := genderSymbol(name); symbol != "" {
+		colorNameGender.Fprint(w, symbol)
+	}
+}
+
+// genderSymbol returns the symbol shown after a name with --show-gender,
+// or an empty string if the gender is unknown or not shown.
+func genderSymbol(name namnsdag.Name) string {
+	if !rootFlags.showGender {
+		return ""
+	}
+	switch name.Gender {
+	case namnsdag.GenderBoy:
+		return "♂"
+	case namnsdag.GenderGirl:
+		return "♀"
+	case namnsdag.GenderBoth:
+		return "⚥"
+	default:
+		return ""
+	}
+}
+
+// cacheMigration is the state of the automatic migration of the cache from
+// older versions.
+type cacheMigration struct {
+	AttemptedAt time.Time `json:"attemptedAt"`
+}
+
+// migrateCacheOnce migrates the cache from older versions, unless that has
+// already been attempted, so that the old files are not migrated again after
+// the cache has been wiped. Returns false if nothing was migrated.
+func migrateCacheOnce() (namnsdag.Cache, bool) {
+	var migration cacheMigration
+	if err := loadState(stateCacheMigration, &migration); err != nil {
+		writeWarning(fmt.Errorf("load cache migration state: %w", err))
+	}
+	if !migration.AttemptedAt.IsZero() {
+		return namnsdag.Cache{}, false
+	}
+	cache, err := namnsdag.MigrateCache()
+	if err := saveState(stateCacheMigration, cacheMigration{AttemptedAt: now()}); err != nil {
+		writeWarning(fmt.Errorf("save cache migration state: %w", err))
+	}
+	if errors.Is(err, namnsdag.ErrNoLegacyCache) {
+		return namnsdag.Cache{}, false
+	}
+	if err != nil {
+		// The old files are only a head start, so fetch the names anew
+		writeWarning(fmt.Errorf("skipping migration of cache from older version: %w", err))
+		return namnsdag.Cache{}, false
+	}
+	colorStatus.Fprintf(stderr, "Migrated cached names for %d days from older version.\n", len(cache.NamesPerDay))
+	return cache, true
 }
 
-func loadOrFetchNames() (map[namnsdag.DoM][]namnsdag.Name, error) {
+// loadOrFetchNames loads the cached names, and fetches them if the cache is
+// outdated. With --light, or if fetching all names failed, only the names of
+// the given day are fetched and merged into the cache.
+func loadOrFetchNames(day time.Time) (namnsdag.Cache, error) {
 	if rootFlags.noCache && rootFlags.noFetch {
-		return nil, errors.New("cannot use --no-cache and --no-fetch at the same time")
+		return namnsdag.Cache{}, errors.New("cannot use --no-cache and --no-fetch at the same time")
 	}
 
-	var cache namnsdag.Cache
+	cache := namnsdag.Cache{KeepDuplicates: cfg.KeepDuplicateNames}
 
 	if !rootFlags.noCache {
-		c, err := namnsdag.LoadCache()
-		if err != nil {
-			return nil, fmt.Errorf("load cached names: %w", err)
+		c, err := loadCache()
+		if errors.Is(err, namnsdag.ErrCacheCorrupt) && !rootFlags.noFetch {
+			colorStatus.Fprintln(stderr, "Cached names are corrupt, ignoring cache.")
+		} else if err != nil {
+			return namnsdag.Cache{}, fmt.Errorf("load cached names: %w", err)
 		}
+		c.KeepDuplicates = cache.KeepDuplicates
 		cache = c
 	}
 
+	if !rootFlags.noCache && !isPinnedDataset() && rootFlags.profile == "" && len(cache.NamesPerDay) == 0 {
+		if c, ok := migrateCacheOnce(); ok {
+			c.KeepDuplicates = cache.KeepDuplicates
+			cache = c
+		}
+	}
+
 	isCacheValid := len(cache.NamesPerDay) > 0
 	if isCacheValid && rootFlags.noFetch {
-		return cache.NamesPerDay, nil
+		return cache, nil
 	}
 
-	isCacheOutdated := !isCacheValid || cache.UpdatedAt.Before(time.Now().Truncate(24*time.Hour))
+	source, err := nameSource()
+	if err != nil {
+		return cache, err
+	}
+	daySource, isDaySource := source.(namnsdag.DaySource)
+	if rootFlags.light && !isDaySource {
+		return cache, errors.New("--light can only be used when fetching from the website")
+	}
+	isSameSource := cache.Source == "" || cache.Source == source.String()
+	isCacheOutdated := !isCacheValid || !isSameSource || cache.Partial || cache.IsOutdated(now())
+	if rootFlags.light && isCacheValid {
+		info := cache.DayInfo(namnsdag.NewDoMFromTime(day))
+		isCacheOutdated = info.UpdatedAt.Before(namnsdag.StartOfDay(now())) || info.Source != source.String()
+	}
+	if isCacheValid && isPinnedDataset() {
+		// Versioned datasets never change, so no need to refetch
+		isCacheOutdated = false
+	}
 	if isCacheOutdated && rootFlags.noFetch {
-		return nil, errors.New("none or outdated cache, and skipping fetch because --no-fetch was supplied")
+		return namnsdag.Cache{}, errors.New("none or outdated cache, and skipping fetch because --no-fetch was supplied")
+	}
+	if isCacheOutdated && isCacheValid && isSameSource && rootFlags.asyncRefresh && !rootFlags.light && !rootFlags.noCache {
+		if err := startBackgroundRefresh(); err != nil {
+			writeWarning(fmt.Errorf("refresh names in the background: %w", err))
+		} else {
+			colorStatus.Fprintln(stderr, "Cached names are outdated, refreshing them in the background.")
+			return cache, nil
+		}
 	}
 
 	if !isCacheOutdated {
-		return cache.NamesPerDay, nil
+		return cache, nil
 	}
 
-	req := namnsdag.Request{ETag: cache.ETag}
-	if !isCacheValid {
-		req.ETag = ""
+	if !rootFlags.noCache {
+		path, err := cacheFile()
+		if err != nil {
+			return cache, err
+		}
+		if err := recordFetchAttempt(path, cache); err != nil {
+			if errors.Is(err, namnsdag.ErrFetchTooSoon) && isCacheValid {
+				colorStatus.Fprintf(stderr, "Cached names are outdated, but not fetching them again yet: %s.\n", err)
+				return cache, nil
+			}
+			return cache, err
+		}
+	}
+
+	if err := runBeforeFetchHook(); err != nil {
+		return cache, err
+	}
+
+	if rootFlags.light {
+		return fetchDayNames(cache, daySource, day)
 	}
 
-	colorStatus.Printf("Fetching names from %s... ", namnsdag.URL)
-	resp, err := namnsdag.Fetch(req)
-	if errors.Is(err, namnsdag.ErrHTTPNotModified) && isCacheValid {
-		colorStatus.Println("cache is up-to-date")
-		return cache.NamesPerDay, nil
+	etag := cache.ETag
+	if !isCacheValid || !isSameSource {
+		etag = ""
+	}
+	req, err := newRequest(etag)
+	if err != nil {
+		return cache, err
+	}
+
+	colorStatus.Fprintf(stderr, "Fetching names from %s... ", source)
+	if rootFlags.verbose {
+		// Timings of the requests are written in between
+		fmt.Fprintln(stderr)
+	}
+	fetchStart := time.Now()
+	resp, err := source.Fetch(req)
+	if errors.Is(err, namnsdag.ErrHTTPNotModified) && isCacheValid && isSameSource {
+		colorStatus.Fprintln(stderr, "cache is up-to-date")
+		cache.UpdatedAt = now()
+		if err := saveCache(cache); err != nil {
+			return cache, fmt.Errorf("cache names: %w", err)
+		}
+		return cache, nil
 	}
 	if err != nil {
-		colorError.Println("error")
-		return cache.NamesPerDay, fmt.Errorf("fetch names: %w", err)
+		colorError.Fprintln(stderr, "error")
+		if isDaySource {
+			writeWarning(fmt.Errorf("fetch names: %w", err))
+			return fetchDayNames(cache, daySource, day)
+		}
+		return cache, fmt.Errorf("fetch names: %w", err)
+	}
+	colorStatus.Fprintf(stderr, "fetched %d names\n", len(resp.Names))
+	if rootFlags.verbose {
+		colorStatus.Fprintf(stderr, "Fetched and parsed the names in %s\n", formatTraceDuration(time.Since(fetchStart)))
 	}
-	colorStatus.Printf("fetched %d names\n", len(resp.Names))
-	cache.SetNames(resp.Names)
-	cache.UpdatedAt = time.Now()
+	if resp.SchemaDrift != nil {
+		writeWarning(fmt.Errorf("%w (use --strict to fail instead)", resp.SchemaDrift))
+	}
+	if rootFlags.force {
+		cache.SetNames(resp.Names)
+	} else if err := cache.UpdateNames(resp.Names); err != nil {
+		writeWarning(fmt.Errorf("%w, keeping the previously cached names (use --force to replace them anyway)", err))
+		return cache, nil
+	}
+	cache.UpdatedAt = now()
 	cache.ETag = resp.ETag
-	if err := namnsdag.SaveCache(cache); err != nil {
-		return cache.NamesPerDay, fmt.Errorf("cache names: %w", err)
+	cache.Source = source.String()
+	if err := saveCache(cache); err != nil {
+		return cache, fmt.Errorf("cache names: %w", err)
+	}
+	if err := runAfterFetchHook(resp.Names); err != nil {
+		return cache, err
 	}
-	return cache.NamesPerDay, nil
+	return cache, nil
 }
 
-func filterOnlyOfficial(names []namnsdag.Name) []namnsdag.Name {
-	var filtered []namnsdag.Name
-	for _, name := range names {
-		if name.TypeOfName != namnsdag.TypeUnofficial {
-			filtered = append(filtered, name)
-		}
+// fetchDayNames fetches only the names of a single day, and merges them into
+// the cache, alongside any names of other days from other sources.
+func fetchDayNames(cache namnsdag.Cache, source namnsdag.DaySource, day time.Time) (namnsdag.Cache, error) {
+	dom := namnsdag.NewDoMFromTime(day)
+	req, err := newRequest("")
+	if err != nil {
+		return cache, err
 	}
-	return filtered
+	colorStatus.Fprintf(stderr, "Fetching names for %s from %s... ", dom, source)
+	resp, err := source.FetchDay(dom, req)
+	if err != nil {
+		colorError.Fprintln(stderr, "error")
+		return cache, fmt.Errorf("fetch names for %s: %w", dom, err)
+	}
+	colorStatus.Fprintf(stderr, "fetched %d names\n", len(resp.Names))
+	if resp.SchemaDrift != nil {
+		writeWarning(fmt.Errorf("%w (use --strict to fail instead)", resp.SchemaDrift))
+	}
+	cache.SetDayNames(dom, resp.Names, namnsdag.DayInfo{
+		UpdatedAt: now(),
+		Source:    source.String(),
+	})
+	if err := saveCache(cache); err != nil {
+		return cache, fmt.Errorf("cache names: %w", err)
+	}
+	if err := runAfterFetchHook(cache.NamesPerDay[dom]); err != nil {
+		return cache, err
+	}
+	return cache, nil
 }
 
-// Execute is the entry point for running this command.
+// Execute is the entry point for running this command. It exits the process
+// on errors.
 func Execute() {
-	err := rootCmd.Execute()
-	if err != nil {
-		writeError(err)
-		os.Exit(1)
+	if err := ExecuteContext(context.Background()); err != nil {
+		var exitErr exitError
+		if !errors.As(err, &exitErr) || exitErr.err != nil {
+			writeError(err)
+		}
+		os.Exit(ExitCode(err))
+	}
+}
+
+// ExecuteContext runs the command given by the arguments in [os.Args], and
+// returns any error instead of exiting the process, such as when embedding
+// the CLI in other programs. Use [ExitCode] to get the exit code of the
+// error.
+func ExecuteContext(ctx context.Context) error {
+	return NewRootCmd().ExecuteContext(ctx)
+}
+
+// ExitCode returns the exit code the CLI exits with for an error returned
+// from [ExecuteContext], such as 1 from "namnsdag is" when the name is not
+// celebrated. Returns 0 for a nil error.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return 1
+}
+
+// NewRootCmd returns the root command with all its subcommands, with all
+// flags and settings reset to their defaults. Use [cobra.Command.SetArgs] to
+// run it with custom arguments, such as in tests, or add it as a subcommand
+// to embed the CLI inside a multi-tool binary. The options replace the
+// dependencies of the commands, such as the clock and the source of names.
+//
+// NewRootCmd is not reentrant: the commands share package-level state, so
+// only one command from this package may run at a time, and calling
+// NewRootCmd again resets the previously returned command.
+func NewRootCmd(opts ...Option) *cobra.Command {
+	resetState()
+	for _, opt := range opts {
+		opt(&injected)
+	}
+	resetFlags(rootCmd)
+	rootCmd.SetArgs(nil)
+	rootCmd.SetIn(nil)
+	rootCmd.SetOut(nil)
+	rootCmd.SetErr(nil)
+	return rootCmd
+}
+
+// defaultNoColor and defaultLogger are the settings from before running any
+// command, restored by [resetState].
+var (
+	defaultNoColor = color.NoColor
+	defaultLogger  = slog.Default()
+)
+
+// resetState resets the package-level state that the commands change when
+// run, other than the flags, to the defaults.
+func resetState() {
+	injected = deps{}
+	cfg = config{
+		MinFetchInterval: namnsdag.DefaultMinFetchInterval,
+	}
+	stdout = os.Stdout
+	stderr = os.Stderr
+	clock = namnsdag.SystemClock
+	location = time.Local
+	decorations.prefix = true
+	decorations.marker = true
+	decorations.emoji = false
+	globalFlags = nil
+	resetTheme()
+	color.NoColor = defaultNoColor
+	slog.SetDefault(defaultLogger)
+	namnsdag.CompressCache = false
+	namnsdag.ReadOnlyCache = false
+	namnsdag.CacheDir = ""
+	namnsdag.StateDir = ""
+}
+
+// resetFlags resets the flags of the command and all its subcommands to
+// their default values, as if they had never been set.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			var values []string
+			if def := strings.Trim(f.DefValue, "[]"); def != "" {
+				values = strings.Split(def, ",")
+			}
+			sv.Replace(values)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
 	}
 }
 
 func init() {
-	rootCmd.Flags().BoolVar(&rootFlags.noFetch, "no-fetch", false, "Skips fetching via HTTP.")
-	rootCmd.Flags().BoolVar(&rootFlags.noCache, "no-cache", false, "Skips loading from cache.")
-	rootCmd.Flags().BoolVar(&rootFlags.noUnofficial, "no-unofficial", false, `Skips showing unofficial namnsdagar, aka "Bolibompa namnsdagar".`)
+	rootCmd.PersistentFlags().BoolVar(&rootFlags.noFetch, "no-fetch", false, "Skips fetching via HTTP.")
+	rootCmd.PersistentFlags().BoolVar(&rootFlags.showGender, "show-gender", false, "Annotate the names with a symbol for boy (♂), girl (♀), or both (⚥), for names whose gender is known.")
+	rootCmd.PersistentFlags().BoolVar(&rootFlags.asyncRefresh, "async-refresh", false, "Show outdated cached names right away, and refresh them in the background for next time, instead of waiting for the fetch.")
+	rootCmd.PersistentFlags().BoolVar(&rootFlags.noCache, "no-cache", false, "Skips loading from cache.")
+	rootCmd.PersistentFlags().BoolVar(&rootFlags.stateless, "stateless", os.Getenv("NAMNSDAG_STATELESS") == "true", "Only read config from NAMNSDAG_* environment variables, keep the names in memory unless NAMNSDAG_CACHE_DIR is set, and write all logs to stdout, eg. in containers. Also enabled by NAMNSDAG_STATELESS=true.")
+	rootCmd.PersistentFlags().StringVar(&rootFlags.configFile, "config", "", "Path to config file (default ~/.config/namnsdag/config.yaml).")
+	rootCmd.PersistentFlags().StringVar(&rootFlags.proxy, "proxy", "", "Proxy URL used when fetching, eg. http://proxy:3128 or socks5://localhost:1080.")
+	rootCmd.PersistentFlags().StringVar(&rootFlags.dataset, "dataset", "", `Use a versioned dataset snapshot, eg. "v2024", instead of fetching the latest names. It is only fetched once and then cached.`)
+	rootCmd.PersistentFlags().StringVar(&rootFlags.source, "source", "", "URL to a JSON dataset of names, eg. a mirror, to use instead of https://dagensnamnsdag.nu.")
+	rootCmd.PersistentFlags().StringVar(&rootFlags.profile, "profile", "", `Named profile, eg. "work", with its own cache file and settings from the "profiles" section of the config file.`)
+	rootCmd.PersistentFlags().StringVar(&rootFlags.publicKey, "public-key", "", "Minisign public key used to verify the signature of the dataset from --dataset or --source.")
+	rootCmd.MarkFlagsMutuallyExclusive("dataset", "source")
+	rootCmd.PersistentFlags().StringVar(&rootFlags.timezone, "timezone", "", `Time zone used to decide what day it is, eg. "Europe/Stockholm" on servers running in UTC (default local time zone).`)
+	rootCmd.PersistentFlags().BoolVar(&rootFlags.weekNumbers, "week-numbers", false, "Shows ISO week numbers in the daily output and in calendars.")
+	rootCmd.PersistentFlags().BoolVar(&rootFlags.noPager, "no-pager", false, "Do not pipe long output, such as from search, through $PAGER.")
+	rootCmd.PersistentFlags().BoolVar(&rootFlags.verbose, "verbose", false, "Writes the timings of each HTTP request to stderr, such as the DNS lookup, connect, and time to first byte, as well as the number of downloaded bytes and the time spent parsing.")
+	rootCmd.PersistentFlags().BoolVar(&rootFlags.debug, "debug", false, "Writes debug logs to stderr, such as how the names were extracted.")
+	rootCmd.PersistentFlags().BoolVar(&rootFlags.strict, "strict", false, "Fail when the fetched data lacks expected fields or contains no names, instead of only warning.")
+	rootCmd.PersistentFlags().StringVar(&rootFlags.recordFixtures, "record-fixtures", "", "Directory to save the raw responses to when fetching, for use as test fixtures.")
+	rootCmd.PersistentFlags().BoolVar(&namnsdag.ReadOnlyCache, "cache-read-only", false, "Loads the cache, but never writes to it, eg. for read-only filesystems.")
+	rootCmd.PersistentFlags().BoolVar(&namnsdag.CompressCache, "compress-cache", false, "Compresses the cache file when writing it.")
+	rootCmd.Flags().StringVarP(&rootFlags.output, "output", "o", outputText, `Output format, one of: text, json, markdown, html, sentence, i3blocks, conky, psobject. Any other format runs the output plugin "namnsdag-output-<format>" found in PATH, with the names as JSON on its stdin.`)
+	rootCmd.PersistentFlags().StringVar(&rootFlags.lang, "lang", langSwedish, "Language of the sentence output format and calendars, one of: sv, en.")
+	rootCmd.PersistentFlags().StringSliceVar(&rootFlags.decorations, "decorations", defaultDecorations, `Decorations of the text output, any of: prefix ("===" before each line), marker ("*" after unofficial names), emoji, or "none".`)
+	rootCmd.Flags().BoolVarP(&rootFlags.print0, "print0", "0", false, "Only write the names, each terminated by a NUL byte instead of newline, eg. for xargs -0.")
+	rootCmd.MarkFlagsMutuallyExclusive("print0", "output")
+	rootCmd.Flags().StringVar(&rootFlags.filter, "filter", "", "Only show names containing the given text, and exit with a non-zero exit code if none matched.")
+	rootCmd.Flags().IntVar(&rootFlags.maxWidth, "max-width", 0, "Maximum width of the text output, where names are wrapped onto new lines. Defaults to the terminal width.")
+	rootCmd.Flags().StringVar(&rootFlags.separator, "separator", defaultSeparator, "Separator between the names in the i3blocks and conky output formats.")
+	rootCmd.Flags().BoolVar(&rootFlags.prompt, "prompt", false, "Only write today's names from the cache as a single line without colors, truncated to --max-width (default 30), eg. for Starship or other shell prompts. Never fetches, and writes nothing if the names are not cached.")
+	rootCmd.Flags().BoolVar(&rootFlags.oncePerDay, "once-per-day", false, `Only show the names if they haven't already been shown today with this flag, eg. when opening a new shell. See "namnsdag shell-init".`)
+	rootCmd.Flags().BoolVar(&rootFlags.softFail, "soft-fail", false, "If fetching fails but there are cached names, even outdated ones, quietly show the cached names and exit with a zero exit code, eg. for status bars.")
+	rootCmd.Flags().BoolVar(&rootFlags.copy, "copy", false, "Also copy the output to the system clipboard, without colors, eg. for pasting into a chat.")
+	rootCmd.Flags().BoolVar(&rootFlags.truncate, "truncate", false, `Truncate the names to a single line within --max-width, ending with "+N more", eg. for status bars.`)
+	rootCmd.Flags().BoolVar(&rootFlags.showUpdated, "show-updated", false, "Shows when the names were last updated, and from where.")
+	rootCmd.Flags().BoolVar(&rootFlags.holidays, "holidays", false, "Also shows Swedish public holidays and flag days on the given day.")
+	rootCmd.Flags().BoolVar(&rootFlags.themeDays, "theme-days", false, `Also shows Swedish theme days, aka "temadagar", on the given day.`)
+	rootCmd.Flags().BoolVar(&rootFlags.light, "light", false, "Only fetch the names of the given day, instead of the names of all days.")
+	rootCmd.Flags().BoolVar(&rootFlags.force, "force", false, "Replace the cached names even if the fetch yielded no names.")
+	rootCmd.PersistentFlags().BoolVar(&rootFlags.noUnofficial, "no-unofficial", false, `Skips showing unofficial namnsdagar, aka "Bolibompa namnsdagar".`)
+	rootCmd.PersistentFlags().BoolVar(&rootFlags.unofficial, "unofficial", false, `Also shows unofficial namnsdagar, overriding the "names-filter" config entry.`)
+	rootCmd.MarkFlagsMutuallyExclusive("no-unofficial", "unofficial")
 }
@@ -0,0 +1,270 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag/namnsdagtest"
+)
+
+var testNames = []namnsdag.Name{
+	namnsdagtest.Name("Henrik", time.October, 17),
+	namnsdagtest.Name("Sten", time.October, 18),
+}
+
+// runCmd runs the CLI with the arguments, isolated from the config, cache,
+// and state files of the user, and returns its output.
+func runCmd(t *testing.T, args []string, opts ...Option) (string, error) {
+	t.Helper()
+	isolateDirs(t)
+	return execCmd(t, args, opts...)
+}
+
+// isolateDirs makes the CLI use empty config, cache, and state directories,
+// so that tests can populate them before calling [execCmd].
+func isolateDirs(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir+"/config")
+	t.Setenv("XDG_CACHE_HOME", dir+"/cache")
+	t.Setenv("XDG_STATE_HOME", dir+"/state")
+}
+
+// execCmd runs the CLI with the arguments, and returns its output.
+func execCmd(t *testing.T, args []string, opts ...Option) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	cmd := NewRootCmd(opts...)
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(append([]string{"--timezone", "UTC"}, args...))
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestRootCmdFetchesTodaysNames(t *testing.T) {
+	source := &namnsdagtest.Source{Names: testNames}
+	out, err := runCmd(t, nil,
+		WithClock(namnsdag.FixedClock(time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC))),
+		WithSource(source),
+		WithStore(namnsdag.NewStore(namnsdag.Cache{})))
+	if err != nil {
+		t.Fatalf("execute: %s\n%s", err, out)
+	}
+	if !strings.Contains(out, "Henrik") || strings.Contains(out, "Sten") {
+		t.Errorf("want only Henrik in output, got:\n%s", out)
+	}
+	if got := len(source.Requests()); got != 1 {
+		t.Errorf("want 1 request to source, got %d", got)
+	}
+}
+
+func TestRootCmdMidnightRollover(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{"before midnight", time.Date(2026, time.October, 17, 23, 59, 59, 0, time.UTC), "Henrik"},
+		{"after midnight", time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC), "Sten"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := runCmd(t, nil,
+				WithClock(namnsdag.FixedClock(tc.now)),
+				WithSource(&namnsdagtest.Source{Names: testNames}),
+				WithStore(namnsdag.NewStore(namnsdag.Cache{})))
+			if err != nil {
+				t.Fatalf("execute: %s\n%s", err, out)
+			}
+			if !strings.Contains(out, tc.want) {
+				t.Errorf("want %s in output, got:\n%s", tc.want, out)
+			}
+		})
+	}
+}
+
+func TestRootCmdUsesFreshCache(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	source := &namnsdagtest.Source{Err: errors.New("should not fetch")}
+	store := namnsdag.NewStore(namnsdagtest.Cache(now, testNames...))
+	out, err := runCmd(t, nil,
+		WithClock(namnsdag.FixedClock(now)),
+		WithSource(source),
+		WithStore(store))
+	if err != nil {
+		t.Fatalf("execute: %s\n%s", err, out)
+	}
+	if !strings.Contains(out, "Henrik") {
+		t.Errorf("want Henrik in output, got:\n%s", out)
+	}
+	if got := len(source.Requests()); got != 0 {
+		t.Errorf("want no requests to source, got %d", got)
+	}
+}
+
+func TestIsCmdExitCode(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"celebrated", []string{"is", "Henrik"}, 0},
+		{"not celebrated", []string{"is", "Sten"}, 1},
+		{"invalid date", []string{"is", "Sten", "not-a-date"}, 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := runCmd(t, tc.args,
+				WithClock(namnsdag.FixedClock(now)),
+				WithStore(namnsdag.NewStore(namnsdagtest.Cache(now, testNames...))))
+			if got := ExitCode(err); got != tc.want {
+				t.Errorf("want exit code %d, got %d (err: %v)", tc.want, got, err)
+			}
+		})
+	}
+}
+
+func TestNewRootCmdResetsState(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	store := namnsdag.NewStore(namnsdagtest.Cache(now, testNames...))
+	out, err := runCmd(t, []string{"--show-gender", "--timezone", "Europe/Stockholm"},
+		WithClock(namnsdag.FixedClock(now)), WithStore(store))
+	if err != nil {
+		t.Fatalf("execute: %s\n%s", err, out)
+	}
+	NewRootCmd()
+	if rootFlags.showGender {
+		t.Error("want --show-gender reset")
+	}
+	if location != time.Local {
+		t.Errorf("want time zone reset to local, got %s", location)
+	}
+	if injected != (deps{}) {
+		t.Error("want injected dependencies reset")
+	}
+}
+
+func TestIsCmdPrint(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	out, err := runCmd(t, []string{"is", "Henrik", "--print"},
+		WithClock(namnsdag.FixedClock(now)),
+		WithStore(namnsdag.NewStore(namnsdagtest.Cache(now, testNames...))))
+	if err != nil {
+		t.Fatalf("execute: %s\n%s", err, out)
+	}
+	if !strings.Contains(out, "Yes, Henrik is celebrated") {
+		t.Errorf("want answer in output, got:\n%s", out)
+	}
+}
+
+func TestRootCmdBuiltInThemeDays(t *testing.T) {
+	now := time.Date(2026, time.October, 4, 12, 0, 0, 0, time.UTC)
+	out, err := runCmd(t, []string{"--theme-days"},
+		WithClock(namnsdag.FixedClock(now)),
+		WithStore(namnsdag.NewStore(namnsdagtest.Cache(now, testNames...))))
+	if err != nil {
+		t.Fatalf("execute: %s\n%s", err, out)
+	}
+	if !strings.Contains(out, "Kanelbullens dag") {
+		t.Errorf("want Kanelbullens dag in output, got:\n%s", out)
+	}
+}
+
+func TestRootCmdShowUpdatedInTimezone(t *testing.T) {
+	updatedAt := time.Date(2026, time.October, 17, 10, 0, 0, 0, time.UTC)
+	now := updatedAt.Add(2 * time.Hour)
+	out, err := runCmd(t, []string{"--show-updated", "--timezone", "Asia/Tokyo"},
+		WithClock(namnsdag.FixedClock(now)),
+		WithStore(namnsdag.NewStore(namnsdagtest.Cache(updatedAt, testNames...))))
+	if err != nil {
+		t.Fatalf("execute: %s\n%s", err, out)
+	}
+	if want := "Names updated 2026-10-17 19:00"; !strings.Contains(out, want) {
+		t.Errorf("want %q in output, got:\n%s", want, out)
+	}
+}
+
+func TestRootCmdRejectsOutputPluginPaths(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	for _, format := range []string{"../bin/sh", "a/b", "JSON5", "x y"} {
+		t.Run(format, func(t *testing.T) {
+			out, err := runCmd(t, []string{"--output", format},
+				WithClock(namnsdag.FixedClock(now)),
+				WithStore(namnsdag.NewStore(namnsdagtest.Cache(now, testNames...))))
+			if err == nil || !strings.Contains(err.Error(), "must only contain") {
+				t.Errorf("want invalid output format error, got %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+func TestRootCmdMigratesCacheOnce(t *testing.T) {
+	isolateDirs(t)
+	b, err := os.ReadFile("../pkg/namnsdag/testdata/migrate/cache@v2.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cacheDir := filepath.Join(os.Getenv("XDG_CACHE_HOME"), "namnsdag")
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, "cache@v2.json"), b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	clock := WithClock(namnsdag.FixedClock(time.Date(2023, time.May, 1, 12, 0, 0, 0, time.UTC)))
+
+	out, err := execCmd(t, nil, clock,
+		WithSource(&namnsdagtest.Source{Err: errors.New("should not fetch")}))
+	if err != nil {
+		t.Fatalf("execute: %s\n%s", err, out)
+	}
+	if !strings.Contains(out, "Migrated cached names for 2 days") {
+		t.Errorf("want migrated names in output, got:\n%s", out)
+	}
+
+	// The old file is left as-is, but is not migrated again once the cache
+	// has been wiped.
+	if err := os.Remove(filepath.Join(cacheDir, fmt.Sprintf("cache@v%d.json", namnsdag.CacheVersion))); err != nil {
+		t.Fatal(err)
+	}
+	source := &namnsdagtest.Source{Names: testNames}
+	out, err = execCmd(t, nil, clock, WithSource(source))
+	if err != nil {
+		t.Fatalf("execute: %s\n%s", err, out)
+	}
+	if strings.Contains(out, "Migrated cached names") {
+		t.Errorf("want no second migration, got:\n%s", out)
+	}
+	if got := len(source.Requests()); got != 1 {
+		t.Errorf("want 1 request to source, got %d", got)
+	}
+}
@@ -0,0 +1,70 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+)
+
+// timeOfDay is a wall clock time, such as 08:00, for daily schedules.
+type timeOfDay struct {
+	hour   int
+	minute int
+}
+
+// parseTimeOfDay parses a time of day on the format HH:MM.
+func parseTimeOfDay(s string) (timeOfDay, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return timeOfDay{}, fmt.Errorf("invalid time of day %q, must be HH:MM", s)
+	}
+	return timeOfDay{t.Hour(), t.Minute()}, nil
+}
+
+// next returns the next occurrence of the time of day after t, in the time
+// zone of t.
+func (tod timeOfDay) next(t time.Time) time.Time {
+	y, m, d := t.Date()
+	next := time.Date(y, m, d, tod.hour, tod.minute, 0, 0, t.Location())
+	if !next.After(t) {
+		next = time.Date(y, m, d+1, tod.hour, tod.minute, 0, 0, t.Location())
+	}
+	return next
+}
+
+func (tod timeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", tod.hour, tod.minute)
+}
+
+// runDaily calls fn every day at the given time of day, in the time zone from
+// --timezone, and never returns. Errors from fn are only written as warnings,
+// so that a failure one day does not stop the following days.
+func runDaily(at timeOfDay, fn func(day time.Time) error) {
+	for {
+		next := at.next(now())
+		colorStatus.Fprintf(stderr, "Next post at %s\n", next.Format("2006-01-02 15:04 MST"))
+		time.Sleep(next.Sub(now()))
+		if err := fn(next); err != nil {
+			writeWarning(err)
+		}
+	}
+}
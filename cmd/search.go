@@ -0,0 +1,119 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+var searchFlags = struct {
+	print0 bool
+	regex  bool
+	months monthFilter
+}{}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search for names containing the query",
+	Long: `Search for names containing the query.
+
+The search is case insensitive, and names that start with the query are
+listed first.
+
+With --regex, the query is instead a Go regular expression matched against the
+names as-is, such as '^(Carl|Karl)'. Use the "(?i)" flag for case insensitive
+matching. See https://pkg.go.dev/regexp/syntax for the syntax.
+
+Use --month, or --from-month and --to-month, to only search the names
+celebrated in some months.`,
+	Example: `  namnsdag search anna
+  namnsdag search --regex '^(Carl|Karl)'
+  namnsdag search --from-month 6 --to-month 8 --regex 'a$'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := searchFlags.months.validate(); err != nil {
+			return err
+		}
+		cache, err := loadOrFetchNames(now())
+		if err != nil {
+			return err
+		}
+		var names []namnsdag.Name
+		if searchFlags.regex {
+			re, err := regexp.Compile(args[0])
+			if err != nil {
+				return fmt.Errorf("parse regex: %w", err)
+			}
+			names = cache.SearchRegexp(re)
+		} else {
+			names = cache.Search(args[0])
+			if resolved, ok := aliases().Resolve(args[0]); ok && len(names) == 0 {
+				names = cache.Search(resolved)
+			}
+		}
+		names = searchFlags.months.filter(filterNames(names))
+		if len(names) == 0 {
+			return fmt.Errorf("no names found matching %q", args[0])
+		}
+		if searchFlags.print0 {
+			return writeNamesNul(names)
+		}
+		defer startPager()()
+		for _, name := range names {
+			writeNameWithDate(name)
+		}
+		return nil
+	},
+}
+
+// writeNameWithDate writes a line on the format "05-18 Erik".
+func writeNameWithDate(name namnsdag.Name) {
+	var sb strings.Builder
+	colorPrefix.Fprint(&sb, name.DoM())
+	sb.WriteByte(' ')
+	writeName(&sb, name)
+	fmt.Fprintln(stdout, sb.String())
+}
+
+// writeNamesNul writes only the names, each terminated by a NUL byte, for
+// use with "xargs -0" and similar.
+func writeNamesNul(names []namnsdag.Name) error {
+	w := bufio.NewWriter(stdout)
+	for _, name := range names {
+		w.WriteString(name.Name)
+		w.WriteByte(0)
+	}
+	return w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().BoolVarP(&searchFlags.regex, "regex", "E", false, "Treat the query as a Go regular expression matched against the names.")
+	searchCmd.Flags().BoolVarP(&searchFlags.print0, "print0", "0", false, "Only write the names, each terminated by a NUL byte instead of newline, eg. for xargs -0.")
+	addMonthFilterFlags(searchCmd, &searchFlags.months)
+}
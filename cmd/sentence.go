@@ -0,0 +1,168 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+)
+
+// Supported languages of the --lang flag.
+const (
+	langSwedish = "sv"
+	langEnglish = "en"
+)
+
+// sentenceLang contains the phrases of the sentence output format in a
+// given language, as well as the words used by the calendars, bots, and
+// notifications.
+type sentenceLang struct {
+	months     [12]string
+	weekdays   [7]string // abbreviated, indexed by [time.Weekday]
+	week       string    // format of week numbers, such as "v. %d"
+	and        string
+	official   string
+	unofficial string
+	serial     bool // use a comma before the last "and", as in "A, B, and C"
+	date       func(day int, month string) string
+	today      func(date string) string
+	onDay      func(date string) string
+	names      func(when, names string) string
+	noNames    func(when string) string
+	nameDay    func(name, dates string) string
+	noDay      func(name string) string
+	title      func(date string) string
+}
+
+var sentenceLangs = map[string]sentenceLang{
+	langSwedish: {
+		months: [12]string{"januari", "februari", "mars", "april", "maj", "juni",
+			"juli", "augusti", "september", "oktober", "november", "december"},
+		weekdays:   [7]string{"sön", "mån", "tis", "ons", "tors", "fre", "lör"},
+		week:       "v. %d",
+		and:        "och",
+		official:   "Officiella namn",
+		unofficial: "Inofficiella namn",
+		date:       func(day int, month string) string { return fmt.Sprintf("%d %s", day, month) },
+		today:      func(date string) string { return "Idag den " + date },
+		onDay:      func(date string) string { return "Den " + date },
+		names:      func(when, names string) string { return fmt.Sprintf("%s firar %s namnsdag.", when, names) },
+		noNames:    func(when string) string { return fmt.Sprintf("%s firar ingen namnsdag.", when) },
+		nameDay:    func(name, dates string) string { return fmt.Sprintf("%s har namnsdag den %s.", name, dates) },
+		noDay:      func(name string) string { return fmt.Sprintf("Hittade ingen namnsdag för %s.", name) },
+		title:      func(date string) string { return "Namnsdagar den " + date },
+	},
+	langEnglish: {
+		months: [12]string{"January", "February", "March", "April", "May", "June",
+			"July", "August", "September", "October", "November", "December"},
+		weekdays:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		week:       "week %d",
+		and:        "and",
+		official:   "Official names",
+		unofficial: "Unofficial names",
+		serial:     true,
+		date:       func(day int, month string) string { return fmt.Sprintf("%s %d", month, day) },
+		today:      func(date string) string { return "Today, " + date + "," },
+		onDay:      func(date string) string { return "On " + date + "," },
+		names:      func(when, names string) string { return fmt.Sprintf("%s it is the name day of %s.", when, names) },
+		noNames:    func(when string) string { return fmt.Sprintf("%s it is nobody's name day.", when) },
+		nameDay:    func(name, dates string) string { return fmt.Sprintf("The name day of %s is on %s.", name, dates) },
+		noDay:      func(name string) string { return fmt.Sprintf("Found no name day for %s.", name) },
+		title:      func(date string) string { return "Name days on " + date },
+	},
+}
+
+func lookupSentenceLang(lang string) (sentenceLang, error) {
+	l, ok := sentenceLangs[lang]
+	if !ok {
+		return sentenceLang{}, fmt.Errorf("unsupported language %q, must be one of: %s, %s", lang, langSwedish, langEnglish)
+	}
+	return l, nil
+}
+
+// writeSentence writes the names as a single natural-language sentence, such
+// as "Idag den 6 juni firar Gustav och Gösta namnsdag.", meant for
+// text-to-speech and voice assistants.
+func writeSentence(w io.Writer, names []namnsdag.Name, day time.Time) error {
+	l, err := lookupSentenceLang(rootFlags.lang)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, l.sentence(names, day, now()))
+	return err
+}
+
+func (l sentenceLang) sentence(names []namnsdag.Name, day, now time.Time) string {
+	date := l.date(day.Day(), l.months[day.Month()-1])
+	when := l.onDay(date)
+	if y, m, d := now.Date(); day.Year() == y && day.Month() == m && day.Day() == d {
+		when = l.today(date)
+	}
+	if len(names) == 0 {
+		return l.noNames(when)
+	}
+	titles := make([]string, len(names))
+	for i, name := range names {
+		titles[i] = name.Name
+	}
+	return l.names(when, l.join(titles))
+}
+
+// nameDaySentence returns a sentence telling which days the names are
+// celebrated, such as "Erik har namnsdag den 18 maj.", where all names are
+// occurrences of the same name. If there are no names, the sentence says
+// that no namnsdag was found for the query.
+func (l sentenceLang) nameDaySentence(query string, names []namnsdag.Name) string {
+	if len(names) == 0 {
+		return l.noDay(query)
+	}
+	dates := make([]string, len(names))
+	for i, name := range names {
+		dates[i] = l.date(name.Day, l.months[name.Month-1])
+	}
+	return l.nameDay(names[0].Name, l.join(dates))
+}
+
+// titleOf returns the title of a notification about the names of a day, such
+// as "Namnsdagar den 6 juni".
+func (l sentenceLang) titleOf(day time.Time) string {
+	return l.title(l.date(day.Day(), l.months[day.Month()-1]))
+}
+
+// join joins the words as a list, such as "A, B och C".
+func (l sentenceLang) join(words []string) string {
+	switch len(words) {
+	case 1:
+		return words[0]
+	case 2:
+		return words[0] + " " + l.and + " " + words[1]
+	}
+	last := len(words) - 1
+	sep := " "
+	if l.serial {
+		sep = ", "
+	}
+	return strings.Join(words[:last], ", ") + sep + l.and + " " + words[last]
+}
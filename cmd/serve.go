@@ -0,0 +1,654 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// serveAddr is the default address that "namnsdag serve" listens on.
+const serveAddr = "localhost:8080"
+
+// serveACMEAddr is the address that "namnsdag serve" listens on when using
+// ACME, as the certificates are verified on the standard HTTPS port.
+const serveACMEAddr = ":https"
+
+// serveShutdownTimeout is how long "namnsdag serve" waits for requests in
+// flight to finish when the context of the command is done.
+const serveShutdownTimeout = 5 * time.Second
+
+// serveDefaultDays and serveMaxDays are the default and maximum number of
+// days returned from the "/api/upcoming" endpoint.
+const (
+	serveDefaultDays = 7
+	serveMaxDays     = 366
+)
+
+// serveConfig contains the settings of "namnsdag serve" in the config file.
+type serveConfig struct {
+	Listen      string          `yaml:"listen"`
+	CORSOrigins []string        `yaml:"cors-origins"`
+	Auth        serveAuthConfig `yaml:"auth"`
+	TLS         serveTLSConfig  `yaml:"tls"`
+}
+
+// serveTLSConfig contains the TLS settings of "namnsdag serve". Either the
+// certificate and key files, or the ACME hosts, may be set.
+type serveTLSConfig struct {
+	Cert      string   `yaml:"cert"`
+	Key       string   `yaml:"key"`
+	ACMEHosts []string `yaml:"acme-hosts"`
+	ACMEEmail string   `yaml:"acme-email"`
+}
+
+// serveAuthConfig contains the credentials required by "namnsdag serve".
+// Authentication is disabled when neither a token nor a username is set.
+type serveAuthConfig struct {
+	Token    string `yaml:"token"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+var serveFlags = struct {
+	listen      string
+	corsOrigins []string
+	username    string
+	tls         serveTLSConfig
+}{}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the names over HTTP, as a JSON API and a small web page",
+	Long: `Serve the names over HTTP, as a JSON API and a small web page.
+
+By default, the server listens on localhost:8080, or on the port given by the
+PORT environment variable, as is the convention of PaaS providers. Use
+--listen to listen on another address, such as ":80" to listen on all
+interfaces.
+
+The following paths are served:
+
+  /                    Web page with the names of a day, and a date picker.
+  /api/names           Names of today, as JSON. Same format as --output json.
+  /api/names?date=...  Names of a given day, on the format YYYY-MM-DD.
+  /api/upcoming        Names of the next 7 days, as a JSON list of days. Use
+                       ?days=... for another number of days, up to 366, and
+                       ?date=... to start on another day.
+  /api/dataset         All names of all days, as a dataset snapshot that
+                       other instances can use via --source.
+  /api/homeassistant   Today's names, made for Home Assistant's REST sensor.
+  /badge.svg           Badge with today's names, same as "namnsdag badge".
+
+The names are fetched again once they are outdated, the same way as when
+running "namnsdag" without a command.
+
+To call the API from web pages on other origins, such as a dashboard, allow
+their origins using --cors-origin, or "*" to allow any origin.
+
+To expose the server beyond localhost, require authentication using either a
+bearer token, set in the NAMNSDAG_SERVE_TOKEN environment variable, or basic
+auth, using --username together with a password set in the
+NAMNSDAG_SERVE_PASSWORD environment variable. Both can also be set in the
+config file.
+
+To serve over HTTPS, either provide a certificate and key using --tls-cert and
+--tls-key, or obtain certificates automatically from Let's Encrypt using
+--acme-host. When using ACME, the server listens on port 443 by default, which
+must be reachable from the internet for the certificates to be issued. The
+certificates are cached in ~/.cache/namnsdag/autocert/.
+
+The server supports systemd socket activation, where it serves on the sockets
+passed by systemd instead of listening on its own address.
+
+Each request is logged as JSON to stderr, with a request ID that is also sent
+in the X-Request-Id response header.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := lookupSentenceLang(rootFlags.lang); err != nil {
+			return err
+		}
+		store, err := loadStore(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		if !cmd.Flags().Changed("cors-origin") {
+			serveFlags.corsOrigins = cfg.Serve.CORSOrigins
+		}
+		auth := serveAuth()
+		if auth.Username != "" && auth.Password == "" {
+			return errors.New("basic auth requires a password, set using NAMNSDAG_SERVE_PASSWORD or in the config file")
+		}
+		var handler http.Handler = newServeHandler(store)
+		handler = withServeAuth(auth, handler)
+		handler = withCORS(serveFlags.corsOrigins, handler)
+		handler = withAccessLog(slog.New(slog.NewJSONHandler(stderr, nil)), store, handler)
+
+		srv := &http.Server{
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		fallbackAddr := serveAddr
+		tlsConfig := serveTLS(cmd)
+		switch {
+		case len(tlsConfig.ACMEHosts) > 0:
+			if tlsConfig.Cert != "" || tlsConfig.Key != "" {
+				return errors.New("cannot use both ACME and certificate files")
+			}
+			m, err := newACMEManager(tlsConfig)
+			if err != nil {
+				return err
+			}
+			fallbackAddr = serveACMEAddr
+			srv.TLSConfig = m.TLSConfig()
+		case tlsConfig.Cert != "" || tlsConfig.Key != "":
+			if tlsConfig.Cert == "" || tlsConfig.Key == "" {
+				return errors.New("TLS requires both a certificate and a key file")
+			}
+			cert, err := tls.LoadX509KeyPair(tlsConfig.Cert, tlsConfig.Key)
+			if err != nil {
+				return fmt.Errorf("load TLS certificate: %w", err)
+			}
+			srv.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+		}
+		listeners, err := listen(listenAddr(serveFlags.listen, cfg.Serve.Listen, fallbackAddr))
+		if err != nil {
+			return err
+		}
+		return serveListeners(cmd.Context(), srv, listeners)
+	},
+}
+
+// serveListeners serves on all listeners until any of them fails, or until
+// the context is done, using TLS if the server has a TLS config.
+func serveListeners(ctx context.Context, srv *http.Server, listeners []net.Listener) error {
+	scheme := "http"
+	if srv.TLSConfig != nil {
+		scheme = "https"
+	}
+	errs := make(chan error, len(listeners))
+	for _, ln := range listeners {
+		colorStatus.Fprintf(stderr, "Serving names on %s://%s/\n", scheme, ln.Addr())
+		go func(ln net.Listener) {
+			if srv.TLSConfig != nil {
+				errs <- srv.ServeTLS(ln, "", "")
+			} else {
+				errs <- srv.Serve(ln)
+			}
+		}(ln)
+	}
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+func newServeHandler(store *namnsdag.Store) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		day, ok := serveDate(w, r)
+		if !ok {
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := writeServePage(w, store, day); err != nil {
+			writeWarning(fmt.Errorf("serve page: %w", err))
+		}
+	})
+	mux.HandleFunc("/api/names", func(w http.ResponseWriter, r *http.Request) {
+		day, ok := serveDate(w, r)
+		if !ok {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(newServeResult(store, day)); err != nil {
+			writeWarning(fmt.Errorf("serve names: %w", err))
+		}
+	})
+	mux.HandleFunc("/api/upcoming", func(w http.ResponseWriter, r *http.Request) {
+		day, ok := serveDate(w, r)
+		if !ok {
+			return
+		}
+		days, ok := serveDays(w, r)
+		if !ok {
+			return
+		}
+		upcoming := store.Upcoming(day, days)
+		for i := range upcoming {
+			names := filterNames(upcoming[i].Names)
+			if names == nil {
+				names = []namnsdag.Name{}
+			}
+			upcoming[i].Names = names
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(upcoming); err != nil {
+			writeWarning(fmt.Errorf("serve upcoming names: %w", err))
+		}
+	})
+	mux.HandleFunc("/api/dataset", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		b, err := json.Marshal(store.Dataset())
+		if err != nil {
+			http.Error(w, "failed to encode dataset", http.StatusInternalServerError)
+			writeWarning(fmt.Errorf("serve dataset: %w", err))
+			return
+		}
+		sum := sha256.Sum256(b)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+		w.Header().Set("Cache-Control", "no-cache")
+		http.ServeContent(w, r, "", store.UpdatedAt(), bytes.NewReader(b))
+	})
+	mux.HandleFunc("/api/homeassistant", func(w http.ResponseWriter, r *http.Request) {
+		day, ok := serveDate(w, r)
+		if !ok {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(newHomeAssistantSensor(store, day)); err != nil {
+			writeWarning(fmt.Errorf("serve Home Assistant sensor: %w", err))
+		}
+	})
+	mux.HandleFunc("/badge.svg", func(w http.ResponseWriter, r *http.Request) {
+		day, ok := serveDate(w, r)
+		if !ok {
+			return
+		}
+		w.Header().Set("Content-Type", "image/svg+xml")
+		io.WriteString(w, renderBadgeSVG(badgeLabel, badgeMessage(filterDayNames(store.Names(namnsdag.NewDoMFromTime(day))))))
+	})
+	return mux
+}
+
+// serveTLS returns the TLS settings of the server, where the flags take
+// precedence over the config file.
+func serveTLS(cmd *cobra.Command) serveTLSConfig {
+	tlsConfig := cfg.Serve.TLS
+	flags := cmd.Flags()
+	if flags.Changed("tls-cert") || flags.Changed("tls-key") || flags.Changed("acme-host") {
+		tlsConfig = serveTLSConfig{ACMEEmail: tlsConfig.ACMEEmail}
+	}
+	if flags.Changed("tls-cert") {
+		tlsConfig.Cert = serveFlags.tls.Cert
+	}
+	if flags.Changed("tls-key") {
+		tlsConfig.Key = serveFlags.tls.Key
+	}
+	if flags.Changed("acme-host") {
+		tlsConfig.ACMEHosts = serveFlags.tls.ACMEHosts
+	}
+	if flags.Changed("acme-email") {
+		tlsConfig.ACMEEmail = serveFlags.tls.ACMEEmail
+	}
+	return tlsConfig
+}
+
+// newACMEManager creates a manager that obtains certificates for the ACME
+// hosts from Let's Encrypt, cached in the cache directory, or only in memory
+// when stateless.
+func newACMEManager(tlsConfig serveTLSConfig) (*autocert.Manager, error) {
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(tlsConfig.ACMEHosts...),
+		Email:      tlsConfig.ACMEEmail,
+	}
+	switch {
+	case cfg.CacheDir != "":
+		m.Cache = autocert.DirCache(filepath.Join(cfg.CacheDir, "autocert"))
+	case !rootFlags.stateless:
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("get certificate cache dir: %w", err)
+		}
+		m.Cache = autocert.DirCache(filepath.Join(dir, "namnsdag", "autocert"))
+	}
+	return m, nil
+}
+
+// withAccessLog writes a log record for each request, with a request ID that
+// is also sent in the X-Request-Id response header. An X-Request-Id header in
+// the request, such as from a reverse proxy, is used as the ID if set.
+func withAccessLog(log *slog.Logger, store *namnsdag.Store, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := r.Header.Get("X-Request-Id")
+		if id == "" || len(id) > 128 {
+			id = newRequestID()
+		}
+		w.Header().Set("X-Request-Id", id)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		cacheStatus := "fresh"
+		if store.UpdatedAt().Before(namnsdag.StartOfDay(now())) {
+			cacheStatus = "stale"
+		}
+		attrs := []slog.Attr{
+			slog.String("requestId", id),
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.RequestURI()),
+			slog.Int("status", rec.status),
+			slog.Int64("bytes", rec.bytes),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("cache", cacheStatus),
+			slog.String("remoteAddr", r.RemoteAddr),
+			slog.String("userAgent", r.UserAgent()),
+		}
+		if forwardedFor := r.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+			attrs = append(attrs, slog.String("forwardedFor", forwardedFor))
+		}
+		log.LogAttrs(r.Context(), slog.LevelInfo, "request", attrs...)
+	})
+}
+
+func newRequestID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// statusRecorder records the status code and size of a response, for the
+// access logs.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets [http.ResponseController] access the underlying writer.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// serveAuth returns the credentials required by the server, where the flags
+// and environment variables take precedence over the config file.
+func serveAuth() serveAuthConfig {
+	auth := cfg.Serve.Auth
+	if serveFlags.username != "" {
+		auth.Username = serveFlags.username
+	}
+	if token := os.Getenv("NAMNSDAG_SERVE_TOKEN"); token != "" {
+		auth.Token = token
+	}
+	if password := os.Getenv("NAMNSDAG_SERVE_PASSWORD"); password != "" {
+		auth.Password = password
+	}
+	return auth
+}
+
+// withServeAuth requires requests to authenticate using either the bearer
+// token or basic auth, whichever are configured.
+func withServeAuth(auth serveAuthConfig, next http.Handler) http.Handler {
+	if auth.Token == "" && auth.Username == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth.Token != "" {
+			if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && secureEqual(token, auth.Token) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		if auth.Username != "" {
+			if username, password, ok := r.BasicAuth(); ok && secureEqual(username, auth.Username) && secureEqual(password, auth.Password) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("WWW-Authenticate", `Basic realm="namnsdag", charset="UTF-8"`)
+		} else {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="namnsdag"`)
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// withCORS allows cross-origin requests from the given origins, where "*"
+// allows any origin. Preflight requests are answered directly, without
+// requiring authentication, as browsers never send credentials in them.
+func withCORS(origins []string, next http.Handler) http.Handler {
+	if len(origins) == 0 {
+		return next
+	}
+	allowed := map[string]bool{}
+	for _, origin := range origins {
+		allowed[strings.TrimSuffix(origin, "/")] = true
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Origin")
+		origin := r.Header.Get("Origin")
+		if origin == "" || (!allowed["*"] && !allowed[origin]) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization")
+			w.Header().Set("Access-Control-Max-Age", "86400")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// homeAssistantMaxState is the maximum length in characters of a Home
+// Assistant sensor's state.
+const homeAssistantMaxState = 255
+
+// homeAssistantSensor is the model of the /api/homeassistant endpoint, made
+// for Home Assistant's REST sensor, so that it can be used without templates:
+// the state is read from "state", and the attributes from "attributes" using
+// json_attributes_path.
+type homeAssistantSensor struct {
+	State      string                  `json:"state"`
+	Attributes homeAssistantAttributes `json:"attributes"`
+}
+
+type homeAssistantAttributes struct {
+	Date            string     `json:"date"`
+	Names           []string   `json:"names"`
+	Official        []string   `json:"official"`
+	Unofficial      []string   `json:"unofficial"`
+	Count           int        `json:"count"`
+	OfficialCount   int        `json:"official_count"`
+	UnofficialCount int        `json:"unofficial_count"`
+	UpdatedAt       *time.Time `json:"updated_at"`
+	FriendlyName    string     `json:"friendly_name"`
+	Icon            string     `json:"icon"`
+}
+
+func newHomeAssistantSensor(store *namnsdag.Store, day time.Time) homeAssistantSensor {
+	names := storeNames(store, day)
+	official, unofficial := splitOfficial(names)
+	attrs := homeAssistantAttributes{
+		Date:            day.Format(time.DateOnly),
+		Names:           nameTitles(names),
+		Official:        nameTitles(official),
+		Unofficial:      nameTitles(unofficial),
+		Count:           len(names),
+		OfficialCount:   len(official),
+		UnofficialCount: len(unofficial),
+		FriendlyName:    "Namnsdag",
+		Icon:            "mdi:cake-variant",
+	}
+	if updatedAt := store.UpdatedAt(); !updatedAt.IsZero() {
+		attrs.UpdatedAt = &updatedAt
+	}
+	state := strings.Join(attrs.Names, ", ")
+	if utf8.RuneCountInString(state) > homeAssistantMaxState {
+		state = truncateRunes(state, homeAssistantMaxState-1) + "…"
+	}
+	return homeAssistantSensor{State: state, Attributes: attrs}
+}
+
+// serveDate returns the day given by the "date" query parameter, or today if
+// not set. Writes an error response and returns false if the request is
+// invalid.
+func serveDate(w http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return time.Time{}, false
+	}
+	date := r.URL.Query().Get("date")
+	if date == "" {
+		return now(), true
+	}
+	day, err := parseDate(date)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid date %q, must be YYYY-MM-DD", date), http.StatusBadRequest)
+		return time.Time{}, false
+	}
+	return day, true
+}
+
+// serveDays returns the number of days given by the "days" query parameter,
+// or [serveDefaultDays] if not set. Writes an error response and returns
+// false if the request is invalid.
+func serveDays(w http.ResponseWriter, r *http.Request) (int, bool) {
+	s := r.URL.Query().Get("days")
+	if s == "" {
+		return serveDefaultDays, true
+	}
+	days, err := strconv.Atoi(s)
+	if err != nil || days < 1 || days > serveMaxDays {
+		http.Error(w, fmt.Sprintf("invalid days %q, must be between 1 and %d", s, serveMaxDays), http.StatusBadRequest)
+		return 0, false
+	}
+	return days, true
+}
+
+// newServeResult is the same as [newNamesResult], but without the watch
+// list, to not expose the contacts of the watch list to the network.
+func newServeResult(store *namnsdag.Store, day time.Time) namesResult {
+	dom := namnsdag.NewDoMFromTime(day)
+	names := filterDayNames(store.Names(dom))
+	result := newDayResult(names, store.DayInfo(dom), store.ETag(), day)
+	result.Watched = nil
+	return result
+}
+
+//go:embed templates/serve.html
+var serveHTML string
+
+var serveTemplate = template.Must(template.New("serve").Parse(serveHTML))
+
+// writeServePage writes the web page with the names of a day.
+func writeServePage(w io.Writer, store *namnsdag.Store, day time.Time) error {
+	l, err := lookupSentenceLang(rootFlags.lang)
+	if err != nil {
+		return err
+	}
+	result := newServeResult(store, day)
+	today := now().Format(time.DateOnly)
+	return serveTemplate.Execute(w, struct {
+		Lang    string
+		Title   string
+		Result  namesResult
+		Prev    string
+		Next    string
+		Today   string
+		IsToday bool
+	}{
+		Lang:    rootFlags.lang,
+		Title:   l.sentence(result.Names, day, now()),
+		Result:  result,
+		Prev:    day.AddDate(0, 0, -1).Format(time.DateOnly),
+		Next:    day.AddDate(0, 0, 1).Format(time.DateOnly),
+		Today:   today,
+		IsToday: result.Date == today,
+	})
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveFlags.listen, "listen", "", `Address to listen on, eg. "127.0.0.1:8080" or ":80" (default "localhost:8080", or ":$PORT" if set).`)
+	serveCmd.Flags().StringSliceVar(&serveFlags.corsOrigins, "cors-origin", nil, `Origins allowed to make cross-origin requests, eg. "https://dashboard.example.com", or "*" for any origin.`)
+	serveCmd.Flags().StringVar(&serveFlags.tls.Cert, "tls-cert", "", "Path to a TLS certificate file, to serve over HTTPS.")
+	serveCmd.Flags().StringVar(&serveFlags.tls.Key, "tls-key", "", "Path to the TLS certificate's private key file.")
+	serveCmd.Flags().StringSliceVar(&serveFlags.tls.ACMEHosts, "acme-host", nil, `Hostnames to obtain TLS certificates for from Let's Encrypt, eg. "namnsdag.example.com".`)
+	serveCmd.Flags().StringVar(&serveFlags.tls.ACMEEmail, "acme-email", "", "Contact email address to register with Let's Encrypt.")
+	serveCmd.Flags().StringVar(&serveFlags.username, "username", "", "Username to require using basic auth. The password is read from NAMNSDAG_SERVE_PASSWORD.")
+}
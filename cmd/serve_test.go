@@ -0,0 +1,58 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag/namnsdagtest"
+)
+
+func TestServeCmdStopsWhenContextIsDone(t *testing.T) {
+	isolateDirs(t)
+	now := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	cmd := NewRootCmd(
+		WithClock(namnsdag.FixedClock(now)),
+		WithStore(namnsdag.NewStore(namnsdagtest.Cache(now, testNames...))))
+	cmd.SetArgs([]string{"--timezone", "UTC", "serve", "--listen", "127.0.0.1:0"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.ExecuteContext(ctx)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("want no error after shutdown, got %s", err)
+		}
+	case <-time.After(serveShutdownTimeout + time.Second):
+		t.Fatal("serve did not stop when the context was done")
+	}
+}
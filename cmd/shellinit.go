@@ -0,0 +1,95 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	shellInitPOSIX = `# Show today's namnsdagar in the first interactive shell of the day
+case $- in
+*i*) command -v namnsdag >/dev/null 2>&1 && namnsdag --once-per-day ;;
+esac
+`
+	shellInitFish = `# Show today's namnsdagar in the first interactive shell of the day
+if status is-interactive; and command -q namnsdag
+    namnsdag --once-per-day
+end
+`
+)
+
+var shellInitCmd = &cobra.Command{
+	Use:   "shell-init <bash|zsh|fish>",
+	Short: "Print a snippet for your shell's startup file to show the names once per day",
+	Long: `Print a snippet for your shell's startup file, which shows today's names the
+first time a shell is opened each day, instead of in every new terminal.
+
+  # ~/.bashrc
+  eval "$(namnsdag shell-init bash)"
+
+  # ~/.zshrc
+  eval "$(namnsdag shell-init zsh)"
+
+  # ~/.config/fish/config.fish
+  namnsdag shell-init fish | source`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash", "zsh":
+			fmt.Fprint(stdout, shellInitPOSIX)
+		case "fish":
+			fmt.Fprint(stdout, shellInitFish)
+		default:
+			return fmt.Errorf("unsupported shell %q, must be one of: bash, zsh, fish", args[0])
+		}
+		return nil
+	},
+}
+
+// lastShown is the model of the --once-per-day state file.
+type lastShown struct {
+	Date string `json:"date"`
+}
+
+// shownToday reports whether the names have already been shown today using
+// --once-per-day.
+func shownToday() bool {
+	var shown lastShown
+	if err := loadState(stateLastShown, &shown); err != nil {
+		return false
+	}
+	return shown.Date == now().Format(time.DateOnly)
+}
+
+// markShownToday records that the names have been shown today, so that
+// --once-per-day skips showing them again until tomorrow.
+func markShownToday() error {
+	return saveState(stateLastShown, lastShown{Date: now().Format(time.DateOnly)})
+}
+
+func init() {
+	rootCmd.AddCommand(shellInitCmd)
+}
@@ -0,0 +1,112 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+type slackNotifyConfig struct {
+	WebhookURL string `yaml:"webhook-url"`
+}
+
+var notifySlackFlags = slackNotifyConfig{}
+
+var notifySlackCmd = &cobra.Command{
+	Use:   "slack",
+	Short: "Post today's names to a Slack channel",
+	Long: `Post today's names to a Slack channel, using an incoming webhook.
+
+The names are formatted using Slack's Block Kit, with the date as header,
+followed by the official and the unofficial names in separate sections. See
+https://api.slack.com/messaging/webhooks for how to create a webhook.`,
+	Example: `  namnsdag notify slack --webhook-url https://hooks.slack.com/services/...
+  namnsdag notify slack --webhook-url https://hooks.slack.com/services/... --at 08:00`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		webhookURL := cfg.Notify.Slack.WebhookURL
+		if cmd.Flags().Changed("webhook-url") {
+			webhookURL = notifySlackFlags.WebhookURL
+		}
+		if webhookURL == "" {
+			return errors.New("missing webhook URL, set using --webhook-url")
+		}
+		l, err := lookupSentenceLang(rootFlags.lang)
+		if err != nil {
+			return err
+		}
+		return runNotify(cmd, "slack "+webhookURL, func(store *namnsdag.Store, day time.Time) error {
+			if err := postJSON(webhookURL, newSlackMessage(l, storeNames(store, day), day)); err != nil {
+				return fmt.Errorf("post to Slack: %w", err)
+			}
+			writeColored(fmt.Sprintf("Posted names of %s to Slack", day.Format(time.DateOnly)))
+			return nil
+		})
+	},
+}
+
+// slackMessage is the model of a Slack message using Block Kit.
+// See https://api.slack.com/block-kit
+type slackMessage struct {
+	// Text is the fallback text, shown in notifications.
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type string    `json:"type"`
+	Text slackText `json:"text"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func newSlackMessage(l sentenceLang, names []namnsdag.Name, day time.Time) slackMessage {
+	msg := slackMessage{
+		Text: l.sentence(names, day, now()),
+		Blocks: []slackBlock{
+			{Type: "header", Text: slackText{Type: "plain_text", Text: l.titleOf(day)}},
+		},
+	}
+	official, unofficial := splitOfficial(names)
+	if len(names) == 0 {
+		msg.Blocks = append(msg.Blocks, slackBlock{Type: "section", Text: slackText{Type: "plain_text", Text: msg.Text}})
+	}
+	if len(official) > 0 {
+		msg.Blocks = append(msg.Blocks, slackBlock{Type: "section", Text: slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", l.official, joinTitles(official))}})
+	}
+	if len(unofficial) > 0 {
+		msg.Blocks = append(msg.Blocks, slackBlock{Type: "section", Text: slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", l.unofficial, joinTitles(unofficial))}})
+	}
+	return msg
+}
+
+func init() {
+	notifyCmd.AddCommand(notifySlackCmd)
+	notifySlackCmd.Flags().StringVar(&notifySlackFlags.WebhookURL, "webhook-url", "", "URL of the Slack incoming webhook.")
+}
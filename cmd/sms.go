@@ -0,0 +1,216 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+// Providers of the "namnsdag notify sms" command.
+const (
+	smsProviderTwilio = "twilio"
+	smsProviderHTTP   = "http"
+)
+
+// twilioAPIURL is the base URL of the Twilio REST API.
+var twilioAPIURL = "https://api.twilio.com"
+
+type smsNotifyConfig struct {
+	To         []string           `yaml:"to"`
+	From       string             `yaml:"from"`
+	Provider   string             `yaml:"provider"`
+	GatewayURL string             `yaml:"gateway-url"`
+	Twilio     twilioNotifyConfig `yaml:"twilio"`
+}
+
+type twilioNotifyConfig struct {
+	AccountSID string `yaml:"account-sid"`
+	AuthToken  string `yaml:"auth-token"`
+}
+
+var notifySMSFlags = smsNotifyConfig{}
+
+var notifySMSCmd = &cobra.Command{
+	Use:   "sms",
+	Short: "Send a text message when someone on the watch list has namnsdag",
+	Long: `Send a text message when someone on the watch list has namnsdag.
+
+Nothing is sent on days when nobody on the watch list has namnsdag. Add names
+to the watch list using "namnsdag contacts import".
+
+The text messages are sent using either Twilio, with the account SID and auth
+token from the "notify.sms.twilio" section of the config file, or a generic
+HTTP gateway, which receives a POST request for each recipient with the JSON
+body:
+
+  {"to": "+46701234567", "from": "namnsdag", "message": "..."}`,
+	Example: `  namnsdag notify sms --to +46701234567 --from +46700000000 --at 08:00
+  namnsdag notify sms --provider http --gateway-url https://sms.example.com/send --to +46701234567`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings := smsNotifySettings(cmd)
+		if len(settings.To) == 0 {
+			return errors.New("missing recipients, set using --to")
+		}
+		var send func(to, message string) error
+		switch settings.Provider {
+		case smsProviderTwilio:
+			if settings.Twilio.AccountSID == "" || settings.Twilio.AuthToken == "" {
+				return errors.New(`missing Twilio credentials, set "notify.sms.twilio" in the config file`)
+			}
+			if settings.From == "" {
+				return errors.New("missing sender phone number, set using --from")
+			}
+			send = func(to, message string) error {
+				return sendTwilioSMS(settings, to, message)
+			}
+		case smsProviderHTTP:
+			if settings.GatewayURL == "" {
+				return errors.New("missing gateway URL, set using --gateway-url")
+			}
+			send = func(to, message string) error {
+				return postJSON(settings.GatewayURL, map[string]string{
+					"to":      to,
+					"from":    settings.From,
+					"message": message,
+				})
+			}
+		default:
+			return fmt.Errorf("unknown SMS provider %q, must be one of: %s, %s", settings.Provider, smsProviderTwilio, smsProviderHTTP)
+		}
+		l, err := lookupSentenceLang(rootFlags.lang)
+		if err != nil {
+			return err
+		}
+		key := fmt.Sprintf("sms %s %s", settings.Provider, strings.Join(settings.To, ","))
+		return runNotify(cmd, key, func(store *namnsdag.Store, day time.Time) error {
+			watched := watchedNames(storeNames(store, day))
+			if len(watched) == 0 {
+				colorStatus.Fprintf(stderr, "Nobody on the watch list has namnsdag on %s\n", day.Format(time.DateOnly))
+				return nil
+			}
+			message := smsMessage(l, watched, day)
+			// Each recipient is marked as notified on its own, so that a
+			// retry after a failure only sends to the remaining recipients.
+			var sent []string
+			var errs []error
+			for _, to := range settings.To {
+				toKey := fmt.Sprintf("sms %s %s", settings.Provider, to)
+				if !notifyFlags.force && alreadyNotified(toKey, day) {
+					continue
+				}
+				if err := send(to, message); err != nil {
+					errs = append(errs, fmt.Errorf("send text message to %s: %w", to, err))
+					continue
+				}
+				sent = append(sent, to)
+				if err := markNotified(toKey, day); err != nil {
+					writeWarning(fmt.Errorf("save notification state: %w", err))
+				}
+			}
+			if len(sent) > 0 {
+				writeColored(fmt.Sprintf("Sent text message to %s", strings.Join(sent, ", ")))
+			}
+			return errors.Join(errs...)
+		})
+	},
+}
+
+// smsNotifySettings returns the settings of the SMS notifier, where the
+// flags take precedence over the config file.
+func smsNotifySettings(cmd *cobra.Command) smsNotifyConfig {
+	settings := cfg.Notify.SMS
+	flags := cmd.Flags()
+	if flags.Changed("to") {
+		settings.To = notifySMSFlags.To
+	}
+	if flags.Changed("from") {
+		settings.From = notifySMSFlags.From
+	}
+	if flags.Changed("provider") || settings.Provider == "" {
+		settings.Provider = notifySMSFlags.Provider
+	}
+	if flags.Changed("gateway-url") {
+		settings.GatewayURL = notifySMSFlags.GatewayURL
+	}
+	return settings
+}
+
+// smsMessage returns a sentence such as "Idag den 17 oktober firar Anna
+// (Henrik) namnsdag.", with the contacts of the watch list entries.
+func smsMessage(l sentenceLang, watched []watchEntry, day time.Time) string {
+	who := make([]string, len(watched))
+	for i, e := range watched {
+		who[i] = e.Name
+		if e.Contact != "" {
+			who[i] = fmt.Sprintf("%s (%s)", e.Contact, e.Name)
+		}
+	}
+	date := l.date(day.Day(), l.months[day.Month()-1])
+	return l.names(l.today(date), l.join(who))
+}
+
+// sendTwilioSMS sends a text message using the Twilio Messaging API.
+// See https://www.twilio.com/docs/messaging/api/message-resource
+func sendTwilioSMS(settings smsNotifyConfig, to, message string) error {
+	client, err := newHTTPClient()
+	if err != nil {
+		return err
+	}
+	form := url.Values{
+		"To":   {to},
+		"From": {settings.From},
+		"Body": {message},
+	}
+	u := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", twilioAPIURL, url.PathEscape(settings.Twilio.AccountSID))
+	req, err := http.NewRequest(http.MethodPost, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", userAgent())
+	req.SetBasicAuth(settings.Twilio.AccountSID, settings.Twilio.AuthToken)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("non-2xx status code: %s", resp.Status)
+	}
+	return nil
+}
+
+func init() {
+	notifyCmd.AddCommand(notifySMSCmd)
+	notifySMSCmd.Flags().StringSliceVar(&notifySMSFlags.To, "to", nil, "Phone numbers to send the text message to, eg. +46701234567.")
+	notifySMSCmd.Flags().StringVar(&notifySMSFlags.From, "from", "", "Phone number or sender name to send the text message from.")
+	notifySMSCmd.Flags().StringVar(&notifySMSFlags.Provider, "provider", smsProviderTwilio, "Provider to send the text message with, one of: twilio, http.")
+	notifySMSCmd.Flags().StringVar(&notifySMSFlags.GatewayURL, "gateway-url", "", `URL of the HTTP gateway, when using "--provider http".`)
+}
@@ -0,0 +1,98 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+)
+
+// nameSource returns the source to fetch names from, based on the --dataset,
+// --source, and --public-key flags.
+func nameSource() (namnsdag.Source, error) {
+	if injected.source != nil {
+		return injected.source, nil
+	}
+	var url string
+	switch {
+	case rootFlags.dataset != "":
+		url = namnsdag.DatasetURL(rootFlags.dataset)
+	case rootFlags.source != "":
+		url = rootFlags.source
+	default:
+		if rootFlags.publicKey != "" {
+			return nil, errors.New("--public-key can only be used together with --dataset or --source")
+		}
+		return namnsdag.WebSource{}, nil
+	}
+	source := namnsdag.DatasetSource{URL: url}
+	if rootFlags.publicKey != "" {
+		pk, err := namnsdag.ParseMinisignPublicKey(rootFlags.publicKey)
+		if err != nil {
+			return nil, err
+		}
+		source.PublicKey = &pk
+	}
+	return source, nil
+}
+
+// isPinnedDataset returns true if a versioned dataset snapshot is used, which
+// never changes and therefore never needs to be refetched once cached.
+func isPinnedDataset() bool {
+	return rootFlags.dataset != ""
+}
+
+// cacheFile returns the path to the cache file, which is separate for each
+// versioned dataset snapshot and for each profile. The dataset snapshots never
+// change, and are therefore shared between all profiles.
+func cacheFile() (string, error) {
+	if isPinnedDataset() {
+		return namnsdag.DatasetCacheFile(rootFlags.dataset)
+	}
+	if rootFlags.profile != "" {
+		return namnsdag.ProfileCacheFile(rootFlags.profile)
+	}
+	return namnsdag.CacheFile()
+}
+
+func loadCache() (namnsdag.Cache, error) {
+	if injected.store != nil {
+		return injected.store.Cache(), nil
+	}
+	path, err := cacheFile()
+	if err != nil {
+		return namnsdag.Cache{}, err
+	}
+	return namnsdag.LoadCacheFile(path)
+}
+
+func saveCache(cache namnsdag.Cache) error {
+	if injected.store != nil {
+		injected.store.Set(cache)
+		return nil
+	}
+	path, err := cacheFile()
+	if err != nil {
+		return err
+	}
+	return namnsdag.SaveCacheFile(path, cache)
+}
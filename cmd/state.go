@@ -0,0 +1,106 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+)
+
+// Names of the state files, which are kept separate from the cache, as the
+// cache may be wiped freely. See [namnsdag.StateFile].
+const (
+	stateCacheMigration = "cache-migration.json"
+	stateFetchAttempts  = "fetch-attempts.json"
+	stateLastShown      = "last-shown.json"
+	stateNotified       = "notified.json"
+	stateUpdateCheck    = "update-check.json"
+)
+
+// loadState reads a JSON state file into v. A missing file is not an error,
+// and leaves v as-is.
+func loadState(name string, v any) error {
+	path, err := namnsdag.StateFile(name)
+	if err != nil {
+		return err
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("parse state file %q: %w", path, err)
+	}
+	return nil
+}
+
+// saveState writes v as JSON to a state file. With --stateless, nothing is
+// written unless the state-dir config entry is set.
+func saveState(name string, v any) error {
+	if rootFlags.stateless && cfg.StateDir == "" {
+		return nil
+	}
+	path, err := namnsdag.StateFile(name)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0600)
+}
+
+// recordFetchAttempt returns an error wrapping [namnsdag.ErrFetchTooSoon] if
+// the cache file was attempted to be fetched less than min-fetch-interval
+// ago, and otherwise records the new attempt. The attempts are kept in the
+// state, so that wiping the cache doesn't bypass the throttling.
+func recordFetchAttempt(cacheFile string, cache namnsdag.Cache) error {
+	attempts := map[string]time.Time{}
+	if err := loadState(stateFetchAttempts, &attempts); err != nil {
+		writeWarning(err)
+	}
+	last := attempts[cacheFile]
+	if cache.LastAttemptAt != nil && cache.LastAttemptAt.After(last) {
+		// Tracked in the cache by older versions
+		last = *cache.LastAttemptAt
+	}
+	attemptAt := now()
+	if err := namnsdag.CheckFetchAllowed(last, attemptAt, cfg.MinFetchInterval); err != nil {
+		return err
+	}
+	attempts[cacheFile] = attemptAt
+	if err := saveState(stateFetchAttempts, attempts); err != nil {
+		return fmt.Errorf("save fetch attempt: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+)
+
+// defaultSeparator is the default separator between the names in the status
+// bar output formats.
+const defaultSeparator = ", "
+
+// writeStatusLine writes the names as a single line of plain text, for status
+// bars that don't accept JSON, such as i3blocks and conky. The names are
+// truncated to --max-width characters, if set.
+func writeStatusLine(w io.Writer, names []namnsdag.Name) error {
+	line := statusLine(names, rootFlags.separator, rootFlags.maxWidth)
+	_, err := fmt.Fprintln(w, line)
+	return err
+}
+
+// statusLine joins the names using the separator, with as many names as fit
+// within the maximum length, followed by "+N" for the names that didn't fit.
+// A maximum length of 0 means no maximum.
+func statusLine(names []namnsdag.Name, sep string, maxLen int) string {
+	titles := make([]string, len(names))
+	for i, name := range names {
+		titles[i] = name.Name
+		if name.TypeOfName.IsUnofficial() && decorations.marker {
+			titles[i] += "*"
+		}
+	}
+	for n := len(titles); n >= 0; n-- {
+		parts := titles[:n:n]
+		if n < len(titles) {
+			parts = append(parts, fmt.Sprintf("+%d", len(titles)-n))
+		}
+		line := strings.Join(parts, sep)
+		if maxLen <= 0 || utf8.RuneCountInString(line) <= maxLen {
+			return line
+		}
+	}
+	return truncateRunes(fmt.Sprintf("+%d", len(titles)), maxLen)
+}
@@ -0,0 +1,71 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+)
+
+// storeRefreshInterval is how often long-running commands check if the names
+// are outdated and need to be fetched again.
+const storeRefreshInterval = time.Hour
+
+// loadStore loads or fetches the names into a store for long-running
+// commands, such as "namnsdag serve", which is kept up-to-date in the
+// background until the context is done.
+func loadStore(ctx context.Context) (*namnsdag.Store, error) {
+	cache, err := loadOrFetchNames(now())
+	if err != nil {
+		return nil, err
+	}
+	store := namnsdag.NewStore(cache)
+	store.Clock = clock
+	go refreshStore(ctx, store)
+	return store, nil
+}
+
+// refreshStore periodically fetches the names again when they are outdated.
+// Errors are only written as warnings, as the store keeps the names it
+// already has. It returns when the context is done.
+func refreshStore(ctx context.Context, store *namnsdag.Store) {
+	ticker := time.NewTicker(storeRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !store.UpdatedAt().Before(namnsdag.StartOfDay(now())) {
+			continue
+		}
+		cache, err := loadOrFetchNames(now())
+		if err != nil {
+			writeWarning(fmt.Errorf("refresh names: %w", err))
+			continue
+		}
+		store.Set(cache)
+	}
+}
@@ -0,0 +1,148 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+var syncCaldavFlags = struct {
+	url      string
+	username string
+	watched  bool
+}{}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync namnsdagar to other services",
+}
+
+var syncCaldavCmd = &cobra.Command{
+	Use:   "caldav",
+	Short: "Create or update yearly recurring namnsdag events on a CalDAV server",
+	Long: `Create or update yearly recurring namnsdag events on a CalDAV server,
+such as Nextcloud or Fastmail.
+
+Creates one event per day with all of the day's names, or with --watched one
+event per name on the watch list (see "namnsdag contacts"). The events have
+stable UIDs, so syncing again updates the existing events instead of creating
+duplicates.
+
+The --url is the URL of the calendar collection. The password is read from
+the NAMNSDAG_CALDAV_PASSWORD environment variable.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := loadOrFetchNames(now())
+		if err != nil {
+			return err
+		}
+		var events []namnsdag.ICalEvent
+		if syncCaldavFlags.watched {
+			list, err := loadWatchList()
+			if err != nil {
+				return err
+			}
+			if len(list) == 0 {
+				return errors.New("the watch list is empty, add names using \"namnsdag contacts import\"")
+			}
+			for _, e := range list {
+				names := findName(cache, e.Name)
+				names = filterNames(names)
+				for _, name := range names {
+					events = append(events, namnsdag.NewNameEvent(name, e.Contact))
+				}
+			}
+		} else {
+			for _, dom := range namnsdag.AllDoMs() {
+				names := cache.NamesPerDay[dom]
+				names = filterNames(names)
+				if len(names) > 0 {
+					events = append(events, namnsdag.NewDayEvent(dom, names))
+				}
+			}
+		}
+
+		client, err := newHTTPClient()
+		if err != nil {
+			return err
+		}
+		for i, event := range events {
+			colorStatus.Fprintf(stderr, "\rSyncing events... %d/%d", i+1, len(events))
+			if err := putCaldavEvent(client, syncCaldavFlags.url, event); err != nil {
+				colorError.Fprintln(stderr, " error")
+				return fmt.Errorf("sync event %q: %w", event.Summary, err)
+			}
+		}
+		colorStatus.Fprintln(stderr)
+		writeColored(fmt.Sprintf("Synced %s to %s", pluralize(len(events), "event"), syncCaldavFlags.url))
+		return nil
+	},
+}
+
+// putCaldavEvent creates or updates an event in a CalDAV calendar
+// collection, as defined in RFC 4791, where the event's resource is named
+// after its UID.
+func putCaldavEvent(client *http.Client, collectionURL string, event namnsdag.ICalEvent) error {
+	u, err := url.Parse(collectionURL)
+	if err != nil {
+		return fmt.Errorf("parse URL: %w", err)
+	}
+	u = u.JoinPath(url.PathEscape(event.UID) + ".ics")
+	var buf bytes.Buffer
+	if err := namnsdag.WriteICalendar(&buf, now(), event); err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPut, u.String(), &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+	req.Header.Set("User-Agent", userAgent())
+	if syncCaldavFlags.username != "" {
+		req.SetBasicAuth(syncCaldavFlags.username, os.Getenv("NAMNSDAG_CALDAV_PASSWORD"))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("non-2xx status code: %s", resp.Status)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.AddCommand(syncCaldavCmd)
+	syncCaldavCmd.Flags().StringVar(&syncCaldavFlags.url, "url", "", "URL of the CalDAV calendar collection, eg. https://cloud.example.com/remote.php/dav/calendars/me/personal/")
+	syncCaldavCmd.Flags().StringVar(&syncCaldavFlags.username, "username", "", "Username for the CalDAV server.")
+	syncCaldavCmd.Flags().BoolVar(&syncCaldavFlags.watched, "watched", false, "Only sync the names on the watch list.")
+	syncCaldavCmd.MarkFlagRequired("url")
+}
@@ -0,0 +1,157 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// theme is the registry of all colors used in the output, keyed by the name
+// used to override them in the "colors" section of the config file.
+var theme = map[string]*color.Color{}
+
+// themeDefaults are the colors of the theme before being overridden by the
+// config file, keyed by their name.
+var themeDefaults = map[string][]color.Attribute{}
+
+// themeColor creates a color and registers it in the theme, so that it can
+// be overridden by the config file.
+func themeColor(name string, value ...color.Attribute) *color.Color {
+	c := color.New(value...)
+	theme[name] = c
+	themeDefaults[name] = value
+	return c
+}
+
+// resetTheme undoes the overrides from [applyTheme].
+func resetTheme() {
+	for name, c := range theme {
+		*c = *color.New(themeDefaults[name]...)
+	}
+}
+
+var namedColors = map[string]color.Attribute{
+	"black":   color.FgBlack,
+	"red":     color.FgRed,
+	"green":   color.FgGreen,
+	"yellow":  color.FgYellow,
+	"blue":    color.FgBlue,
+	"magenta": color.FgMagenta,
+	"cyan":    color.FgCyan,
+	"white":   color.FgWhite,
+}
+
+var namedStyles = map[string]color.Attribute{
+	"bold":      color.Bold,
+	"faint":     color.Faint,
+	"italic":    color.Italic,
+	"underline": color.Underline,
+}
+
+// applyTheme overrides the colors in the theme, keyed by their name.
+func applyTheme(colors map[string]string) error {
+	for name, spec := range colors {
+		c, ok := theme[name]
+		if !ok {
+			return fmt.Errorf("unknown color %q, must be one of: %s", name, strings.Join(themeNames(), ", "))
+		}
+		attrs, err := parseColorSpec(spec)
+		if err != nil {
+			return fmt.Errorf("color %q: %w", name, err)
+		}
+		*c = *color.New(attrs...)
+	}
+	return nil
+}
+
+func themeNames() []string {
+	names := make([]string, 0, len(theme))
+	for name := range theme {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseColorSpec parses a space-separated list of colors and styles, such as
+// "bright-cyan italic". Colors are either named ANSI colors, optionally
+// prefixed with "bright-", a 256-color palette index such as "208", or a
+// truecolor hex value such as "#ff8800". Colors prefixed with "on-" are used
+// as the background color. The spec "default" means no color at all.
+func parseColorSpec(spec string) ([]color.Attribute, error) {
+	var attrs []color.Attribute
+	for _, field := range strings.Fields(spec) {
+		if field == "default" {
+			continue
+		}
+		if style, ok := namedStyles[field]; ok {
+			attrs = append(attrs, style)
+			continue
+		}
+		field, background := strings.CutPrefix(field, "on-")
+		c, err := parseColor(field, background)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, c...)
+	}
+	return attrs, nil
+}
+
+func parseColor(s string, background bool) ([]color.Attribute, error) {
+	extended := color.Attribute(38)
+	if background {
+		extended = 48
+	}
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		rgb, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || len(hex) != 6 {
+			return nil, fmt.Errorf("invalid truecolor %q, must be on the format #rrggbb", s)
+		}
+		return []color.Attribute{extended, 2,
+			color.Attribute(rgb >> 16 & 0xff),
+			color.Attribute(rgb >> 8 & 0xff),
+			color.Attribute(rgb & 0xff)}, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 || n > 255 {
+			return nil, fmt.Errorf("invalid 256-color %q, must be between 0 and 255", s)
+		}
+		return []color.Attribute{extended, 5, color.Attribute(n)}, nil
+	}
+	name, bright := strings.CutPrefix(s, "bright-")
+	attr, ok := namedColors[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown color or style %q", s)
+	}
+	if bright {
+		attr += color.FgHiBlack - color.FgBlack
+	}
+	if background {
+		attr += color.BgBlack - color.FgBlack
+	}
+	return []color.Attribute{attr}, nil
+}
@@ -0,0 +1,108 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+)
+
+// themeDaysOn returns the Swedish theme days ("temadagar") on a given day,
+// from the built-in list, or from the dataset set via theme-days-source. The
+// dataset is cached separately from the names, and is refetched daily using
+// its ETag.
+//
+// Any errors are only written as warnings, as the theme days are not
+// essential to the output.
+func themeDaysOn(day time.Time) []namnsdag.Name {
+	cache, err := loadOrFetchThemeDays()
+	if err != nil {
+		writeWarning(fmt.Errorf("theme days: %w", err))
+	}
+	return cache.NamesPerDay[namnsdag.NewDoMFromTime(day)]
+}
+
+func loadOrFetchThemeDays() (namnsdag.Cache, error) {
+	if cfg.ThemeDaysSource == "" {
+		var cache namnsdag.Cache
+		cache.SetNames(namnsdag.ThemeDays())
+		return cache, nil
+	}
+	path, err := namnsdag.ThemeDaysCacheFile()
+	if err != nil {
+		return namnsdag.Cache{}, err
+	}
+	var cache namnsdag.Cache
+	if !rootFlags.noCache {
+		cache, err = namnsdag.LoadCacheFile(path)
+		if err != nil && !errors.Is(err, namnsdag.ErrCacheCorrupt) {
+			return namnsdag.Cache{}, fmt.Errorf("load cached theme days: %w", err)
+		}
+	}
+	source := namnsdag.DatasetSource{URL: cfg.ThemeDaysSource}
+	isCacheValid := len(cache.NamesPerDay) > 0 && cache.Source == source.String()
+	isCacheOutdated := !isCacheValid || cache.IsOutdated(now())
+	if !isCacheOutdated || rootFlags.noFetch {
+		return cache, nil
+	}
+	if !rootFlags.noCache {
+		err := recordFetchAttempt(path, cache)
+		if errors.Is(err, namnsdag.ErrFetchTooSoon) {
+			return cache, nil
+		} else if err != nil {
+			return cache, err
+		}
+	}
+
+	etag := cache.ETag
+	if !isCacheValid {
+		etag = ""
+	}
+	req, err := newRequest(etag)
+	if err != nil {
+		return cache, err
+	}
+	colorStatus.Fprintf(stderr, "Fetching theme days from %s... ", source)
+	resp, err := source.Fetch(req)
+	if errors.Is(err, namnsdag.ErrHTTPNotModified) && isCacheValid {
+		colorStatus.Fprintln(stderr, "cache is up-to-date")
+		cache.UpdatedAt = now()
+		return cache, namnsdag.SaveCacheFile(path, cache)
+	}
+	if err != nil {
+		colorError.Fprintln(stderr, "error")
+		return cache, fmt.Errorf("fetch: %w", err)
+	}
+	colorStatus.Fprintf(stderr, "fetched %d theme days\n", len(resp.Names))
+	if err := cache.UpdateNames(resp.Names); err != nil {
+		return cache, err
+	}
+	cache.UpdatedAt = now()
+	cache.ETag = resp.ETag
+	cache.Source = source.String()
+	if err := namnsdag.SaveCacheFile(path, cache); err != nil {
+		return cache, fmt.Errorf("cache theme days: %w", err)
+	}
+	return cache, nil
+}
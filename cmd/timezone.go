@@ -0,0 +1,67 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+)
+
+// location is the time zone used to decide what day it is, set using
+// --timezone or the timezone config entry. Defaults to the local time zone.
+var location = time.Local
+
+// clock tells the current time. It is only replaced via [WithClock], such as in
+// tests, to check date-boundary behavior such as midnight rollovers.
+var clock namnsdag.Clock = namnsdag.SystemClock
+
+// now returns the current time from the clock, in the time zone from
+// --timezone.
+func now() time.Time {
+	return clock.Now().In(location)
+}
+
+// inLocation returns the time in the time zone from --timezone, such as
+// when printing when the names were fetched.
+func inLocation(t time.Time) time.Time {
+	return t.In(location)
+}
+
+// parseDate parses a date on the format YYYY-MM-DD in the time zone from
+// --timezone.
+func parseDate(s string) (time.Time, error) {
+	return time.ParseInLocation(time.DateOnly, s, location)
+}
+
+func loadTimezone(name string) error {
+	if name == "" {
+		location = time.Local
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("load time zone: %w", err)
+	}
+	location = loc
+	return nil
+}
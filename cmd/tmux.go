@@ -0,0 +1,134 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+var tmuxFlags = struct {
+	color     string
+	maxLength int
+}{}
+
+var tmuxCmd = &cobra.Command{
+	Use:   "tmux",
+	Short: "Print today's names as a segment for the tmux status line",
+	Long: `Print today's names as a segment for the tmux status line, using tmux's
+format strings for the color, such as "#[fg=yellow]Gustav, Gösta#[default]".
+
+The segment is cached for the rest of the day, so that it is cheap to run on
+every status-interval. Add it to your ~/.tmux.conf using:
+
+  set -g status-right '#(namnsdag tmux)'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := tmuxCacheFile()
+		if err != nil {
+			return err
+		}
+		key := tmuxCacheKey()
+		if segment, ok := readTmuxCache(path, key); ok {
+			fmt.Fprintln(stdout, segment)
+			return nil
+		}
+		day := now()
+		cache, err := loadOrFetchNames(day)
+		if err != nil {
+			return err
+		}
+		segment := tmuxSegment(namesForToday(cache, day), tmuxFlags.color, tmuxFlags.maxLength)
+		fmt.Fprintln(stdout, segment)
+		if !namnsdag.ReadOnlyCache && !rootFlags.noCache {
+			if err := writeTmuxCache(path, key, segment); err != nil {
+				writeWarning(fmt.Errorf("cache tmux segment: %w", err))
+			}
+		}
+		return nil
+	},
+}
+
+// tmuxSegment formats the names using tmux's format strings. Any "#" is
+// escaped, to not be interpreted by tmux.
+func tmuxSegment(names []namnsdag.Name, color string, maxLength int) string {
+	if len(names) == 0 {
+		return ""
+	}
+	line := strings.ReplaceAll(statusLine(names, defaultSeparator, maxLength), "#", "##")
+	if color == "" {
+		return line
+	}
+	return fmt.Sprintf("#[fg=%s]%s#[default]", color, line)
+}
+
+func tmuxCacheFile() (string, error) {
+	cacheFile, err := namnsdag.CacheFile()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(cacheFile), "tmux.txt"), nil
+}
+
+// tmuxCacheKey identifies the cached segment, which is only valid for the
+// same day and settings.
+func tmuxCacheKey() string {
+	path, _ := cacheFile()
+	filter, _ := namesFilter()
+	return fmt.Sprintf("%s %s %s %s %d %v", now().Format(time.DateOnly), path,
+		filter, tmuxFlags.color, tmuxFlags.maxLength, decorations.marker)
+}
+
+// readTmuxCache returns the cached segment, where the first line of the file
+// is the key of the segment on the second line.
+func readTmuxCache(path, key string) (string, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() || scanner.Text() != key {
+		return "", false
+	}
+	scanner.Scan()
+	return scanner.Text(), scanner.Err() == nil
+}
+
+func writeTmuxCache(path, key, segment string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(key+"\n"+segment+"\n"), 0600)
+}
+
+func init() {
+	rootCmd.AddCommand(tmuxCmd)
+	tmuxCmd.Flags().StringVar(&tmuxFlags.color, "color", "yellow", `Foreground color of the segment in tmux's format, eg. "colour214" or "#ff8800", or empty for no color.`)
+	tmuxCmd.Flags().IntVar(&tmuxFlags.maxLength, "max-length", 0, `Maximum number of characters, where the names that don't fit are replaced with eg. "+2".`)
+}
@@ -0,0 +1,174 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tracingTransport is a [http.RoundTripper] that writes the timings of each
+// request to stderr, such as the DNS lookup and time to first byte, as well
+// as the number of downloaded bytes. Used with --verbose, to tell slow
+// networks apart from slow parsing.
+type tracingTransport struct {
+	Next http.RoundTripper
+}
+
+var _ http.RoundTripper = tracingTransport{}
+
+// RoundTrip implements [http.RoundTripper].
+func (t tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tr := &requestTrace{method: req.Method, url: req.URL.Redacted(), start: time.Now()}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), tr.clientTrace()))
+	resp, err := t.Next.RoundTrip(req)
+	if err != nil {
+		tr.write(fmt.Sprintf("error: %s", err))
+		return nil, err
+	}
+	tr.status = resp.Status
+	resp.Body = &tracingBody{ReadCloser: resp.Body, trace: tr}
+	return resp, nil
+}
+
+// requestTrace records the time of each step of a request, relative to when
+// it started.
+type requestTrace struct {
+	method string
+	url    string
+	status string
+	start  time.Time
+
+	mu                 sync.Mutex
+	dnsStart, dns      time.Duration
+	connectStart, conn time.Duration
+	tlsStart, tls      time.Duration
+	firstByte          time.Duration
+	didDNS, didConnect bool
+	written            bool
+}
+
+func (tr *requestTrace) since() time.Duration {
+	return time.Since(tr.start)
+}
+
+func (tr *requestTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			tr.mu.Lock()
+			tr.dnsStart = tr.since()
+			tr.didDNS = true
+			tr.mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			tr.mu.Lock()
+			tr.dns = tr.since() - tr.dnsStart
+			tr.mu.Unlock()
+		},
+		ConnectStart: func(string, string) {
+			tr.mu.Lock()
+			tr.connectStart = tr.since()
+			tr.didConnect = true
+			tr.mu.Unlock()
+		},
+		ConnectDone: func(string, string, error) {
+			tr.mu.Lock()
+			tr.conn = tr.since() - tr.connectStart
+			tr.mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			tr.mu.Lock()
+			tr.tlsStart = tr.since()
+			tr.mu.Unlock()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			tr.mu.Lock()
+			tr.tls = tr.since() - tr.tlsStart
+			tr.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			tr.mu.Lock()
+			tr.firstByte = tr.since()
+			tr.mu.Unlock()
+		},
+	}
+}
+
+// write writes the timings to stderr, only once per request.
+func (tr *requestTrace) write(result string) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.written {
+		return
+	}
+	tr.written = true
+	var steps []string
+	if tr.didDNS {
+		steps = append(steps, "dns "+formatTraceDuration(tr.dns))
+	}
+	if tr.didConnect {
+		steps = append(steps, "connect "+formatTraceDuration(tr.conn))
+	}
+	if tr.tls > 0 {
+		steps = append(steps, "tls "+formatTraceDuration(tr.tls))
+	}
+	if tr.firstByte > 0 {
+		steps = append(steps, "first byte "+formatTraceDuration(tr.firstByte))
+	}
+	steps = append(steps, "total "+formatTraceDuration(tr.since()))
+	colorStatus.Fprintf(stderr, "%s %s: %s (%s)\n", tr.method, tr.url, result, strings.Join(steps, ", "))
+}
+
+func formatTraceDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
+
+// tracingBody counts the bytes read from a response body, and writes the
+// request's timings once the body has been read or closed.
+type tracingBody struct {
+	io.ReadCloser
+	trace *requestTrace
+	bytes int64
+}
+
+func (b *tracingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes += int64(n)
+	if err == io.EOF {
+		b.trace.write(b.result())
+	}
+	return n, err
+}
+
+func (b *tracingBody) Close() error {
+	b.trace.write(b.result())
+	return b.ReadCloser.Close()
+}
+
+func (b *tracingBody) result() string {
+	return fmt.Sprintf("%s, %d bytes", b.trace.status, b.bytes)
+}
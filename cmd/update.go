@@ -0,0 +1,136 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	latestReleaseURL    = "https://api.github.com/repos/jilleJr/namnsdag/releases/latest"
+	updateCheckInterval = 7 * 24 * time.Hour
+	updateCheckTimeout  = 3 * time.Second
+)
+
+// updateCheck is the model of the update check's state file.
+type updateCheck struct {
+	CheckedAt     time.Time `json:"checkedAt"`
+	LatestVersion string    `json:"latestVersion"`
+}
+
+// notifyIfUpdateAvailable prints a notice to stderr if a newer version of
+// namnsdag has been released. GitHub is queried at most once per week, and
+// any errors are silently ignored as the check is not essential.
+func notifyIfUpdateAvailable() {
+	current := version()
+	if !cfg.CheckForUpdates || parseVersion(current) == nil {
+		return
+	}
+	var check updateCheck
+	loadState(stateUpdateCheck, &check)
+	if now().Sub(check.CheckedAt) >= updateCheckInterval {
+		latest, err := fetchLatestVersion()
+		if err != nil {
+			return
+		}
+		check = updateCheck{CheckedAt: now(), LatestVersion: latest}
+		saveState(stateUpdateCheck, check)
+	}
+	if isNewerVersion(check.LatestVersion, current) {
+		colorStatus.Fprintf(stderr, "A new version of namnsdag is available: %s (current: %s)\n",
+			check.LatestVersion, current)
+	}
+}
+
+func fetchLatestVersion() (string, error) {
+	client, err := newHTTPClient()
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodGet, latestReleaseURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", userAgent())
+	c := *client
+	c.Timeout = updateCheckTimeout
+	resp, err := c.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("non-200 status code: %s", resp.Status)
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", err
+	}
+	return release.TagName, nil
+}
+
+// isNewerVersion reports whether version a is newer than version b, where
+// both are on the format "v1.2.3". Invalid versions are never newer.
+func isNewerVersion(a, b string) bool {
+	va, vb := parseVersion(a), parseVersion(b)
+	if va == nil || vb == nil {
+		return false
+	}
+	for i := range va {
+		if va[i] != vb[i] {
+			return va[i] > vb[i]
+		}
+	}
+	return false
+}
+
+// parseVersion parses a version on the format "v1.2.3", ignoring any
+// pre-release or build suffix. Returns nil if the version is invalid.
+func parseVersion(v string) []int {
+	v, ok := strings.CutPrefix(v, "v")
+	if !ok {
+		return nil
+	}
+	if i := strings.IndexAny(v, "-+"); i != -1 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	nums := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil
+		}
+		nums[i] = n
+	}
+	return nums
+}
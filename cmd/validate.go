@@ -0,0 +1,144 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+// Supported values of the --schema flag.
+const (
+	schemaDataset = "dataset"
+	schemaCache   = "cache"
+)
+
+var validateFlags = struct {
+	schema      string
+	printSchema bool
+}{}
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <file>",
+	Short: "Check a dataset or cache file against its JSON Schema",
+	Long: `Check a dataset or cache file against its JSON Schema, such as a custom
+dataset used via --source, and list all problems found.
+
+The file is validated as a cache file if it contains the "namesPerDay" field,
+and otherwise as a dataset, unless --schema is set. Use --print-schema to
+print the JSON Schema instead, eg. for editors or other validators.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if validateFlags.printSchema {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if validateFlags.printSchema {
+			schema, err := lookupSchema(validateFlags.schema)
+			if err != nil {
+				return err
+			}
+			_, err = stdout.Write(schema)
+			return err
+		}
+		data, err := readMaybeGzip(args[0])
+		if err != nil {
+			return err
+		}
+		kind := validateFlags.schema
+		if kind == "" {
+			kind = detectSchema(data)
+		}
+		var validate func([]byte) error
+		switch kind {
+		case schemaDataset:
+			validate = namnsdag.ValidateDataset
+		case schemaCache:
+			validate = namnsdag.ValidateCacheJSON
+		default:
+			return fmt.Errorf("unknown schema %q, must be one of: %s, %s", kind, schemaDataset, schemaCache)
+		}
+		err = validate(data)
+		var invalid *namnsdag.ValidationError
+		if errors.As(err, &invalid) {
+			for _, problem := range invalid.Problems {
+				writeColored(colorError.Sprint(problem))
+			}
+			return fmt.Errorf("%s is not a valid %s file, found %s", args[0], kind, pluralize(len(invalid.Problems), "problem"))
+		} else if err != nil {
+			return err
+		}
+		writeColored(fmt.Sprintf("%s is a valid %s file", args[0], kind))
+		return nil
+	},
+}
+
+func lookupSchema(kind string) ([]byte, error) {
+	switch kind {
+	case schemaDataset, "":
+		return namnsdag.DatasetSchema, nil
+	case schemaCache:
+		return namnsdag.CacheSchema, nil
+	default:
+		return nil, fmt.Errorf("unknown schema %q, must be one of: %s, %s", kind, schemaDataset, schemaCache)
+	}
+}
+
+// detectSchema guesses the kind of file, where only cache files contain the
+// "namesPerDay" field.
+func detectSchema(data []byte) string {
+	if bytes.Contains(data, []byte(`"namesPerDay"`)) {
+		return schemaCache
+	}
+	return schemaDataset
+}
+
+// readMaybeGzip reads a file, which is decompressed if it is gzip
+// compressed, such as cache files written with compress-cache.
+func readMaybeGzip(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		return data, nil
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decompress %s: %w", path, err)
+	}
+	defer gz.Close()
+	return io.ReadAll(gz)
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().StringVar(&validateFlags.schema, "schema", "", "Schema to validate against, one of: dataset, cache (default detected from the file).")
+	validateCmd.Flags().BoolVar(&validateFlags.printSchema, "print-schema", false, "Print the JSON Schema selected by --schema, instead of validating a file.")
+}
@@ -0,0 +1,106 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+// Build metadata, set via ldflags. For example:
+//
+//	go build -ldflags "-X github.com/jilleJr/namnsdag/v3/cmd.buildVersion=v3.1.0"
+//
+// When unset, the values are read from the Go build info instead.
+var (
+	buildVersion string
+	buildCommit  string
+	buildDate    string
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version of namnsdag",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Fprintf(stdout, "namnsdag %s\n", version())
+		fmt.Fprintf(stdout, "  commit:       %s\n", orUnknown(commit()))
+		fmt.Fprintf(stdout, "  built:        %s\n", orUnknown(buildTime()))
+		fmt.Fprintf(stdout, "  go:           %s\n", runtime.Version())
+		fmt.Fprintf(stdout, "  cache schema: v%d\n", namnsdag.CacheVersion)
+		return nil
+	},
+}
+
+// version returns the version this program was built from, such as
+// "v3.1.0", or "(devel)" when built from a local checkout.
+func version() string {
+	if buildVersion != "" {
+		return buildVersion
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "" {
+		return "(devel)"
+	}
+	return info.Main.Version
+}
+
+func commit() string {
+	if buildCommit != "" {
+		return buildCommit
+	}
+	return buildSetting("vcs.revision")
+}
+
+func buildTime() string {
+	if buildDate != "" {
+		return buildDate
+	}
+	return buildSetting("vcs.time")
+}
+
+func buildSetting(key string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == key {
+			return setting.Value
+		}
+	}
+	return ""
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}
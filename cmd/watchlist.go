@@ -0,0 +1,130 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"gopkg.in/yaml.v3"
+)
+
+// watchEntry is the model of an entry in the watch list file: a name to
+// watch for, and who carries it.
+type watchEntry struct {
+	Name    string `yaml:"name" json:"name"`
+	Contact string `yaml:"contact,omitempty" json:"contact,omitempty"`
+}
+
+// watchListFile returns the path to the watch list file, which is stored
+// alongside the config file.
+func watchListFile() (string, error) {
+	path, err := configFile()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "watchlist.yaml"), nil
+}
+
+// loadWatchList reads the watch list, which is empty if the file does not
+// exist.
+func loadWatchList() ([]watchEntry, error) {
+	path, err := watchListFile()
+	if err != nil {
+		return nil, fmt.Errorf("get watch list file path: %w", err)
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var entries []watchEntry
+	if err := yaml.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("parse watch list file %q: %w", path, err)
+	}
+	return entries, nil
+}
+
+func saveWatchList(entries []watchEntry) error {
+	path, err := watchListFile()
+	if err != nil {
+		return fmt.Errorf("get watch list file path: %w", err)
+	}
+	b, err := yaml.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0600)
+}
+
+// addToWatchList adds entries to the watch list, skipping any duplicates.
+// Returns the number of added entries.
+func addToWatchList(list []watchEntry, entries ...watchEntry) ([]watchEntry, int) {
+	type key struct{ name, contact string }
+	seen := map[key]bool{}
+	for _, e := range list {
+		seen[key{namnsdag.NormalizeName(e.Name), e.Contact}] = true
+	}
+	var added int
+	for _, e := range entries {
+		k := key{namnsdag.NormalizeName(e.Name), e.Contact}
+		if e.Name == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		list = append(list, e)
+		added++
+	}
+	return list, added
+}
+
+// watchedNames returns the entries of the watch list that match any of the
+// given names. Errors reading the watch list are only written as warnings.
+func watchedNames(names []namnsdag.Name) []watchEntry {
+	list, err := loadWatchList()
+	if err != nil {
+		writeWarning(fmt.Errorf("watch list: %w", err))
+		return nil
+	}
+	celebrating := map[string]bool{}
+	for _, name := range names {
+		celebrating[namnsdag.NormalizeName(name.Name)] = true
+	}
+	var matches []watchEntry
+	aliases := aliases()
+	for _, e := range list {
+		name := e.Name
+		if resolved, ok := aliases.Resolve(name); ok && !celebrating[namnsdag.NormalizeName(name)] {
+			name = resolved
+		}
+		if celebrating[namnsdag.NormalizeName(name)] {
+			matches = append(matches, e)
+		}
+	}
+	return matches
+}
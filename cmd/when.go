@@ -0,0 +1,61 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+var whenCmd = &cobra.Command{
+	Use:   "when <name>",
+	Short: "Show which day a name is celebrated",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := loadOrFetchNames(now())
+		if err != nil {
+			return err
+		}
+		names := findName(cache, args[0])
+		names = filterNames(names)
+		if len(names) == 0 {
+			return fmt.Errorf("no namnsdag found for %q", args[0])
+		}
+		var days []string
+		for _, name := range names {
+			days = append(days, formatDoM(name.DoM()))
+		}
+		writeColored(fmt.Sprintf("%s: %s", colorNameOfficial.Sprint(names[0].Name), strings.Join(days, ", ")))
+		return nil
+	},
+}
+
+// formatDoM formats a day-of-month as eg. "May 18".
+func formatDoM(dom namnsdag.DoM) string {
+	return fmt.Sprintf("%s %d", dom.Month, dom.Day)
+}
+
+func init() {
+	rootCmd.AddCommand(whenCmd)
+}
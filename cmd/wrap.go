@@ -0,0 +1,127 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"golang.org/x/term"
+)
+
+// outputWidth returns the maximum width of the text output, from --max-width
+// or else the width of the terminal. Returns 0 for no maximum width, such as
+// when stdout is not a terminal.
+func outputWidth() int {
+	if rootFlags.maxWidth > 0 {
+		return rootFlags.maxWidth
+	}
+	f, ok := stdout.(*os.File)
+	if !ok || !isTerminal(f) {
+		return 0
+	}
+	width, _, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// layoutNames joins the names to fit within the given width, where the first
+// line starts at the indent column. Names that don't fit are either wrapped
+// onto new lines with a hanging indent, or with truncate set, replaced with
+// "+N more". A width of 0 means no maximum width.
+func layoutNames(names []namnsdag.Name, indent, width int, truncate bool) string {
+	widths := make([]int, len(names))
+	for i, name := range names {
+		widths[i] = utf8.RuneCountInString(name.Name)
+		if name.TypeOfName.IsUnofficial() && decorations.marker {
+			widths[i]++
+		}
+		widths[i] += utf8.RuneCountInString(genderSymbol(name))
+	}
+	if truncate && width > 0 {
+		return truncateNames(names, widths, width-indent)
+	}
+
+	var sb strings.Builder
+	col := indent
+	for i, name := range names {
+		if i > 0 {
+			colorNameDelimiter.Fprint(&sb, ",")
+			col++
+			if width > 0 && col+1+widths[i] > width {
+				sb.WriteString("\n" + strings.Repeat(" ", indent))
+				col = indent
+			} else {
+				sb.WriteByte(' ')
+				col++
+			}
+		}
+		writeName(&sb, name)
+		col += widths[i]
+	}
+	return sb.String()
+}
+
+// truncateNames joins as many names as fit within the width on one line,
+// followed by "+N more" for the names that didn't fit.
+func truncateNames(names []namnsdag.Name, widths []int, width int) string {
+	// Find the largest number of names that fit together with the suffix.
+	n := len(names)
+	for ; n > 0; n-- {
+		total := 0
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				total += len(", ")
+			}
+			total += widths[i]
+		}
+		if n < len(names) {
+			total += len(moreSuffix(len(names)-n, n > 0))
+		}
+		if total <= width {
+			break
+		}
+	}
+
+	var sb strings.Builder
+	for i, name := range names[:n] {
+		if i > 0 {
+			colorNameDelimiter.Fprint(&sb, ", ")
+		}
+		writeName(&sb, name)
+	}
+	if n < len(names) {
+		colorNameDelimiter.Fprint(&sb, moreSuffix(len(names)-n, n > 0))
+	}
+	return sb.String()
+}
+
+func moreSuffix(more int, afterNames bool) string {
+	if afterNames {
+		return fmt.Sprintf(", +%d more", more)
+	}
+	return fmt.Sprintf("+%d more", more)
+}
@@ -0,0 +1,238 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/spf13/cobra"
+)
+
+const (
+	yearOutputHTML = "html"
+	yearOutputPDF  = "pdf"
+)
+
+var yearFlags = struct {
+	output string
+}{}
+
+var yearCmd = &cobra.Command{
+	Use:   "year [YYYY]",
+	Short: "Write a printable calendar of all namnsdagar in a year",
+	Long: `Write a printable calendar of all namnsdagar in a year, defaulting to the
+current year, with one page per month, suitable for printing as a wall
+calendar.
+
+Sundays and public holidays are printed in red, as in Swedish calendars. The
+calendar is written to stdout, either as an HTML page or as a PDF, and its
+language is set using --lang.`,
+	Example: `  namnsdag year 2025 --output pdf > namnsdagar-2025.pdf
+  namnsdag year --lang en > namnsdagar.html`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		year := now().Year()
+		if len(args) == 1 {
+			var err error
+			year, err = strconv.Atoi(args[0])
+			if err != nil || year < 1 {
+				return fmt.Errorf("invalid year: %q", args[0])
+			}
+		}
+		lang, err := lookupSentenceLang(rootFlags.lang)
+		if err != nil {
+			return err
+		}
+		cache, err := loadOrFetchNames(now())
+		if err != nil {
+			if cache.NamesPerDay == nil {
+				return err
+			}
+			writeWarning(err)
+		}
+		months := newCalendarYear(cache, year, lang)
+		switch yearFlags.output {
+		case yearOutputHTML:
+			return writeYearHTML(stdout, year, months)
+		case yearOutputPDF:
+			return writeYearPDF(stdout, year, months)
+		default:
+			return fmt.Errorf("unknown output format %q, must be one of: %s, %s", yearFlags.output, yearOutputHTML, yearOutputPDF)
+		}
+	},
+}
+
+var yearHTMLTemplate = template.Must(template.New("year").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Namnsdagar {{.Year}}</title>
+<style>
+@page { size: A4; margin: 15mm; }
+body { font-family: sans-serif; margin: 0; }
+section { break-after: page; }
+h1 { font-size: 28pt; font-weight: normal; margin: 0 0 4mm; }
+table { width: 100%; border-collapse: collapse; font-size: 10pt; }
+td { border-bottom: 0.3mm solid #ccc; padding: 1.2mm 2mm; vertical-align: top; }
+td.day { width: 8mm; text-align: right; font-weight: bold; }
+td.weekday { width: 10mm; color: #666; }
+td.week { width: 14mm; color: #666; font-size: 8pt; }
+td.holiday { text-align: right; color: #c00; font-size: 8pt; }
+tr.red td.day, tr.red td.weekday { color: #c00; }
+.unofficial { font-style: italic; color: #666; }
+</style>
+</head>
+<body>
+{{- range .Months}}
+<section>
+<h1>{{.Title}} {{$.Year}}</h1>
+<table>
+{{- range .Days}}
+<tr{{if .RedDay}} class="red"{{end}}>
+<td class="day">{{.Date.Day}}</td>
+<td class="weekday">{{.Weekday}}</td>
+{{- if $.WeekNumbers}}
+<td class="week">{{.WeekText}}</td>
+{{- end}}
+<td class="names">{{range $i, $n := .Names}}{{if $i}}, {{end}}{{if $n.TypeOfName.IsUnofficial}}<span class="unofficial">{{$n.Name}}</span>{{else}}{{$n.Name}}{{end}}{{end}}</td>
+<td class="holiday">{{.HolidayNames}}</td>
+</tr>
+{{- end}}
+</table>
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+// writeYearHTML writes the calendar as an HTML page styled for printing,
+// with one page per month.
+func writeYearHTML(w io.Writer, year int, months []calendarMonth) error {
+	return yearHTMLTemplate.Execute(w, struct {
+		Year        int
+		Months      []calendarMonth
+		WeekNumbers bool
+	}{year, months, rootFlags.weekNumbers})
+}
+
+// writeYearPDF writes the calendar as an A4 PDF, with one page per month.
+func writeYearPDF(w io.Writer, year int, months []calendarMonth) error {
+	const (
+		margin        = 15.0
+		rowHeight     = 8.0
+		dayWidth      = 10.0
+		weekdayWidth  = 12.0
+		weekWidth     = 14.0
+		holidayWidth  = 50.0
+		fontSize      = 10.0
+		minFontSize   = 6.0
+		titleFontSize = 28.0
+	)
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(false, margin)
+	pdf.SetTitle(fmt.Sprintf("Namnsdagar %d", year), true)
+	pdf.SetCreator("namnsdag "+version(), true)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pageWidth, _ := pdf.GetPageSize()
+	namesWidth := pageWidth - 2*margin - dayWidth - weekdayWidth - holidayWidth
+	if rootFlags.weekNumbers {
+		namesWidth -= weekWidth
+	}
+
+	for _, month := range months {
+		pdf.AddPage()
+		pdf.SetFont("Helvetica", "", titleFontSize)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.CellFormat(0, 14, tr(fmt.Sprintf("%s %d", month.Title, year)), "", 1, "L", false, 0, "")
+		pdf.SetDrawColor(0xcc, 0xcc, 0xcc)
+		for _, day := range month.Days {
+			if day.RedDay {
+				pdf.SetTextColor(0xcc, 0, 0)
+			} else {
+				pdf.SetTextColor(0, 0, 0)
+			}
+			pdf.SetFont("Helvetica", "B", fontSize)
+			pdf.CellFormat(dayWidth, rowHeight, strconv.Itoa(day.Date.Day()), "B", 0, "R", false, 0, "")
+			pdf.SetFont("Helvetica", "", fontSize)
+			pdf.CellFormat(weekdayWidth, rowHeight, tr(day.Weekday), "B", 0, "L", false, 0, "")
+			if rootFlags.weekNumbers {
+				pdf.SetTextColor(0x66, 0x66, 0x66)
+				pdf.SetFont("Helvetica", "", 8)
+				pdf.CellFormat(weekWidth, rowHeight, tr(day.WeekText), "B", 0, "L", false, 0, "")
+			}
+
+			pdf.SetTextColor(0, 0, 0)
+			names := tr(joinNamesPlain(day.Names))
+			pdf.SetFont("Helvetica", "", fitFontSize(pdf, names, namesWidth-2, fontSize, minFontSize))
+			pdf.CellFormat(namesWidth, rowHeight, names, "B", 0, "L", false, 0, "")
+
+			pdf.SetTextColor(0xcc, 0, 0)
+			holidays := tr(day.HolidayNames())
+			pdf.SetFont("Helvetica", "", fitFontSize(pdf, holidays, holidayWidth-2, 8, minFontSize))
+			pdf.CellFormat(holidayWidth, rowHeight, holidays, "B", 1, "R", false, 0, "")
+		}
+	}
+	if err := pdf.Error(); err != nil {
+		return fmt.Errorf("render pdf: %w", err)
+	}
+	if err := pdf.Output(w); err != nil {
+		return fmt.Errorf("write pdf: %w", err)
+	}
+	return nil
+}
+
+// fitFontSize returns the largest font size, down to the minimum size, at
+// which the text fits within the width.
+func fitFontSize(pdf *fpdf.Fpdf, text string, width, size, minSize float64) float64 {
+	for ; size > minSize; size -= 0.5 {
+		pdf.SetFontSize(size)
+		if pdf.GetStringWidth(text) <= width {
+			break
+		}
+	}
+	return size
+}
+
+// joinNamesPlain joins the names without colors, marking unofficial names
+// with "*" unless disabled using --decorations.
+func joinNamesPlain(names []namnsdag.Name) string {
+	titles := make([]string, len(names))
+	for i, name := range names {
+		titles[i] = name.Name
+		if name.TypeOfName.IsUnofficial() && decorations.marker {
+			titles[i] += "*"
+		}
+	}
+	return strings.Join(titles, ", ")
+}
+
+func init() {
+	rootCmd.AddCommand(yearCmd)
+
+	yearCmd.Flags().StringVarP(&yearFlags.output, "output", "o", yearOutputHTML, "Output format, one of: html, pdf.")
+}
@@ -22,7 +22,13 @@
 // cmd package.
 package main
 
-import "github.com/jilleJr/namnsdag/v3/cmd"
+import (
+	// Embedding the time zone database, for --timezone to work on systems
+	// without one, such as Windows and minimal containers.
+	_ "time/tzdata"
+
+	"github.com/jilleJr/namnsdag/v3/cmd"
+)
 
 func main() {
 	cmd.Execute()
@@ -0,0 +1,79 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+// Aliases maps nicknames to the names they are short for, such as "Lasse" to
+// "Lars", as nicknames rarely have a namnsdag of their own.
+type Aliases map[string]string
+
+// DefaultAliases contains common Swedish nicknames.
+var DefaultAliases = Aliases{
+	"Affe":   "Alf",
+	"Bettan": "Elisabet",
+	"Bosse":  "Bo",
+	"Gurra":  "Gustav",
+	"Hasse":  "Hans",
+	"Janne":  "Jan",
+	"Jocke":  "Joakim",
+	"Kalle":  "Karl",
+	"Kicki":  "Kristina",
+	"Lasse":  "Lars",
+	"Lelle":  "Lennart",
+	"Lotta":  "Charlotta",
+	"Micke":  "Mikael",
+	"Nicke":  "Niklas",
+	"Nisse":  "Nils",
+	"Olle":   "Olof",
+	"Pelle":  "Per",
+	"Sigge":  "Sigurd",
+	"Tompa":  "Tomas",
+	"Totte":  "Torsten",
+}
+
+// Resolve returns the name that an alias is short for, where the alias is
+// compared using [NormalizeName].
+func (a Aliases) Resolve(alias string) (string, bool) {
+	normalized := NormalizeName(alias)
+	for nickname, name := range a {
+		if NormalizeName(nickname) == normalized {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Merge returns a copy of the aliases, with the other aliases added on top,
+// replacing any aliases of the same nickname.
+func (a Aliases) Merge(other Aliases) Aliases {
+	merged := make(Aliases, len(a)+len(other))
+	for nickname, name := range a {
+		merged[nickname] = name
+	}
+	for nickname, name := range other {
+		for existing := range merged {
+			if NormalizeName(existing) == NormalizeName(nickname) {
+				delete(merged, existing)
+			}
+		}
+		merged[nickname] = name
+	}
+	return merged
+}
@@ -21,34 +21,212 @@
 package namnsdag
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
 	"encoding"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
 	"time"
 )
 
+// CacheVersion is the version of the cache file's schema. It is bumped
+// whenever the schema changes in a backward incompatible way, and is written
+// to the cache file as [Cache.SchemaVersion].
+const CacheVersion = 3
+
+// CompressCache enables gzip compression of the cache file written by
+// [SaveCache]. Both compressed and uncompressed cache files are read
+// transparently by [LoadCache].
+var CompressCache = false
+
+// ReadOnlyCache disables all writes to the cache file, such as for read-only
+// filesystems or containers with a mounted pre-warmed cache. The cache is
+// still loaded as usual, but [SaveCache] and [SaveCacheFile] silently skip
+// writing, and [ClearCache] returns [ErrCacheReadOnly].
+var ReadOnlyCache = false
+
+// CacheDir overrides the directory of the cache files, such as for a mounted
+// volume in a container. Defaults to "namnsdag" inside [os.UserCacheDir].
+var CacheDir = ""
+
+// DefaultMinFetchInterval is the recommended minimum duration between fetch
+// attempts, to not overload the upstream website with requests.
+const DefaultMinFetchInterval = 10 * time.Minute
+
 // Errors specific to the cache.
 var (
 	ErrCacheAlreadyCleared = errors.New("cache already cleared")
+	ErrCacheEmpty          = errors.New("cache contains no names")
+	ErrCacheCorrupt        = errors.New("cache is corrupt")
+	ErrFetchTooSoon        = errors.New("too soon since last fetch attempt")
+	ErrNoNamesToUpdate     = errors.New("refusing to replace cached names with no names")
+	ErrCacheReadOnly       = errors.New("cache is read-only")
+	ErrCacheTooNew         = errors.New("cache was produced by a newer namnsdag")
 )
 
 // Cache is the model representing the cached data.
 type Cache struct {
+	// SchemaVersion is the [CacheVersion] the cache was written with. It is
+	// set by [WriteCache]. Caches written before the field was introduced
+	// have it unset, and use the schema of version 3.
+	SchemaVersion int `json:"schemaVersion,omitempty"`
+
 	ETag        string         `json:"etag"`
 	UpdatedAt   time.Time      `json:"updatedAt"`
 	NamesPerDay map[DoM][]Name `json:"namesPerDay"`
+
+	// Source is the URL the names were fetched from.
+	Source string `json:"source,omitempty"`
+
+	// LastAttemptAt is when names were last attempted to be fetched,
+	// successful or not, or nil if never. Used to throttle fetches via
+	// [Cache.CheckFetchAllowed].
+	LastAttemptAt *time.Time `json:"lastAttemptAt,omitempty"`
+
+	// Checksum is a hash of the names, used to detect partially written or
+	// otherwise corrupted cache files. It is set by [WriteCache] and
+	// verified by [ReadCache].
+	Checksum string `json:"checksum,omitempty"`
+
+	// Partial is true when the cache only contains the names of some days,
+	// as fetched one day at a time using [Cache.SetDayNames], and has never
+	// had all names fetched.
+	Partial bool `json:"partial,omitempty"`
+
+	// Days contains when, and from where, the names of individual days were
+	// last fetched using [Cache.SetDayNames]. This allows partial fetches,
+	// such as from different sources, to coexist in the same cache.
+	// See [Cache.DayInfo].
+	Days map[DoM]DayInfo `json:"days,omitempty"`
+
+	// KeepDuplicates disables the merging of duplicate names in
+	// [Cache.AddNames].
+	KeepDuplicates bool `json:"-"`
+}
+
+// DayInfo is the freshness metadata of a single day's names in a [Cache].
+type DayInfo struct {
+	// UpdatedAt is when the day's names were last fetched.
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Source is the URL the day's names were fetched from.
+	Source string `json:"source,omitempty"`
+}
+
+func (c Cache) checksum() (string, error) {
+	b, err := json.Marshal(c.NamesPerDay)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
 }
 
 // SetNames replaces the names of the map.
 func (c *Cache) SetNames(names []Name) {
 	c.NamesPerDay = nil
+	c.Partial = false
+	c.Days = nil
 	c.AddNames(names)
 }
 
+// SetDayNames replaces the names of a single day, and records when and from
+// where they were fetched in [Cache.Days]. Names on other days are ignored.
+//
+// The cache is marked as [Cache.Partial] if it did not already contain the
+// names of all days.
+func (c *Cache) SetDayNames(dom DoM, names []Name, info DayInfo) {
+	if len(c.NamesPerDay) == 0 {
+		c.Partial = true
+	}
+	if c.NamesPerDay == nil {
+		c.NamesPerDay = make(map[DoM][]Name)
+	}
+	var dayNames []Name
+	for _, name := range names {
+		if name.DoM() == dom {
+			dayNames = append(dayNames, name)
+		}
+	}
+	if !c.KeepDuplicates {
+		dayNames = DedupNames(dayNames)
+	}
+	c.NamesPerDay[dom] = dayNames
+	if c.Days == nil {
+		c.Days = make(map[DoM]DayInfo)
+	}
+	c.Days[dom] = info
+}
+
+// DayInfo returns when, and from where, the names of a day were last
+// fetched. This is the newest of the day's entry in [Cache.Days] and the
+// fetch of all names, if any. The zero value is returned if the day's names
+// have never been fetched.
+func (c Cache) DayInfo(dom DoM) DayInfo {
+	info, ok := c.Days[dom]
+	if !c.Partial && (!ok || c.UpdatedAt.After(info.UpdatedAt)) {
+		return DayInfo{UpdatedAt: c.UpdatedAt, Source: c.Source}
+	}
+	return info
+}
+
+// IsOutdated reports whether the names were last fetched before the start
+// of the day of now, in the time zone of now. Use [time.Time.In] to check
+// against another time zone than the local one.
+func (c Cache) IsOutdated(now time.Time) bool {
+	return c.UpdatedAt.Before(StartOfDay(now))
+}
+
+// SortedDays returns the days that have names, sorted using [SortDoMs]. Use
+// this instead of ranging over [Cache.NamesPerDay] when the order matters,
+// such as in output, as the iteration order of maps is random.
+func (c Cache) SortedDays() []DoM {
+	doms := make([]DoM, 0, len(c.NamesPerDay))
+	for dom := range c.NamesPerDay {
+		doms = append(doms, dom)
+	}
+	SortDoMs(doms)
+	return doms
+}
+
+// StaleDays returns all days of the year, including the 29th of February,
+// whose names have not been fetched since the given time, in calendar order.
+func (c Cache) StaleDays(since time.Time) []DoM {
+	var stale []DoM
+	for _, dom := range AllDoMs() {
+		if c.DayInfo(dom).UpdatedAt.Before(since) {
+			stale = append(stale, dom)
+		}
+	}
+	return stale
+}
+
+// UpdateNames replaces the names of the map, the same as [Cache.SetNames],
+// but returns [ErrNoNamesToUpdate] and leaves the cache untouched if there
+// are no new names while the cache already contains names. This guards
+// against losing a good cache when a fetch unexpectedly yields no names,
+// such as after a redesign of the website.
+func (c *Cache) UpdateNames(names []Name) error {
+	if len(names) == 0 && len(c.NamesPerDay) > 0 {
+		return ErrNoNamesToUpdate
+	}
+	c.SetNames(names)
+	return nil
+}
+
 // AddNames adds names to the map of names, on their appropriate dates.
+//
+// Unless [Cache.KeepDuplicates] is set, names occurring multiple times on the
+// same day are merged using [DedupNames].
 func (c *Cache) AddNames(names []Name) {
 	if c.NamesPerDay == nil {
 		c.NamesPerDay = make(map[DoM][]Name, len(names))
@@ -57,6 +235,99 @@ func (c *Cache) AddNames(names []Name) {
 		dom := NewDoM(name.Month, name.Day)
 		c.NamesPerDay[dom] = append(c.NamesPerDay[dom], name)
 	}
+	if c.KeepDuplicates {
+		return
+	}
+	for dom, dayNames := range c.NamesPerDay {
+		c.NamesPerDay[dom] = DedupNames(dayNames)
+	}
+}
+
+// DedupNames merges names that occur multiple times on the same day,
+// compared using [NormalizeName]. Of the duplicates, the name with the most
+// specific type is kept, where official names are preferred over unofficial
+// names, and their [Name.Variants] are merged. The order of the names is
+// preserved.
+func DedupNames(names []Name) []Name {
+	type key struct {
+		dom  DoM
+		name string
+	}
+	indices := make(map[key]int, len(names))
+	deduped := make([]Name, 0, len(names))
+	for _, name := range names {
+		k := key{name.DoM(), NormalizeName(name.Name)}
+		i, ok := indices[k]
+		if !ok {
+			indices[k] = len(deduped)
+			deduped = append(deduped, name)
+			continue
+		}
+		variants := mergeVariants(deduped[i].Variants, name.Variants)
+		if typeRank(name.TypeOfName) > typeRank(deduped[i].TypeOfName) {
+			deduped[i] = name
+		}
+		deduped[i].Variants = variants
+	}
+	return deduped
+}
+
+// typeRank ranks how specific a type is. Higher is more specific.
+func typeRank(t Type) int {
+	switch {
+	case t.IsOfficial():
+		return 3
+	case t.IsNew():
+		return 2
+	case t.IsUnofficial():
+		return 1
+	default:
+		return 0
+	}
+}
+
+// CheckFetchAllowed returns an error wrapping [ErrFetchTooSoon] if less than
+// minInterval has passed since the last fetch attempt.
+func (c Cache) CheckFetchAllowed(now time.Time, minInterval time.Duration) error {
+	if c.LastAttemptAt == nil {
+		return nil
+	}
+	return CheckFetchAllowed(*c.LastAttemptAt, now, minInterval)
+}
+
+// CheckFetchAllowed returns an error wrapping [ErrFetchTooSoon] if less than
+// minInterval has passed since the last fetch attempt, such as when the
+// attempts are tracked outside of the cache.
+func CheckFetchAllowed(lastAttemptAt, now time.Time, minInterval time.Duration) error {
+	next := lastAttemptAt.Add(minInterval)
+	if now.Before(next) {
+		return fmt.Errorf("%w, next attempt allowed in %s",
+			ErrFetchTooSoon, next.Sub(now).Round(time.Second))
+	}
+	return nil
+}
+
+// Validate returns an error if the cache contains no names, or if any of the
+// days or names are invalid.
+func (c Cache) Validate() error {
+	if len(c.NamesPerDay) == 0 {
+		return ErrCacheEmpty
+	}
+	for _, dom := range c.SortedDays() {
+		names := c.NamesPerDay[dom]
+		if err := dom.Validate(); err != nil {
+			return err
+		}
+		for _, name := range names {
+			if err := name.Validate(); err != nil {
+				return fmt.Errorf("%w (%s)", err, dom)
+			}
+			if name.DoM() != dom {
+				return fmt.Errorf("name %q is dated %s but stored under %s", name.Name, name.DoM(), dom)
+			}
+		}
+	}
+	return nil
 }
 
 // DoM (Day-of-Month) represents a day in a month, no matter what year.
@@ -85,6 +356,50 @@ func (d DoM) String() string {
 	return string(b)
 }
 
+// Before reports whether the day comes before the other day in calendar
+// order.
+func (d DoM) Before(other DoM) bool {
+	if d.Month != other.Month {
+		return d.Month < other.Month
+	}
+	return d.Day < other.Day
+}
+
+// SortDoMs sorts the days in calendar order.
+func SortDoMs(doms []DoM) {
+	sort.Slice(doms, func(i, j int) bool {
+		return doms[i].Before(doms[j])
+	})
+}
+
+// Validate returns an error if the month or day is out of range. The 29th of
+// February is considered valid.
+func (d DoM) Validate() error {
+	if d.Month < time.January || d.Month > time.December {
+		return fmt.Errorf("invalid month: %d", d.Month)
+	}
+	// Using a leap year to allow the 29th of February
+	daysInMonth := time.Date(2000, d.Month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if d.Day < 1 || d.Day > daysInMonth {
+		return fmt.Errorf("invalid day of %s: %d", d.Month, d.Day)
+	}
+	return nil
+}
+
+// AllDoMs returns all days of the year in calendar order, including the 29th
+// of February.
+func AllDoMs() []DoM {
+	doms := make([]DoM, 0, 366)
+	for month := time.January; month <= time.December; month++ {
+		// Using a leap year to include the 29th of February
+		daysInMonth := time.Date(2000, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		for day := 1; day <= daysInMonth; day++ {
+			doms = append(doms, NewDoM(month, day))
+		}
+	}
+	return doms
+}
+
 // NewDoMFromTime creates a new [DoM] based on the month and day in the
 // given time. The year, as well as any hours, minutes, seconds, milliseconds,
 // and time zone is ignored.
@@ -93,6 +408,29 @@ func NewDoMFromTime(t time.Time) DoM {
 	return NewDoM(month, day)
 }
 
+// Today returns today's [DoM] in the given time zone, such as
+// Europe/Stockholm, which may differ from the local time zone on servers
+// running in UTC. A nil location means the local time zone.
+func Today(loc *time.Location) DoM {
+	return TodayAt(SystemClock, loc)
+}
+
+// TodayAt returns today's [DoM] according to the clock, in the given time
+// zone. A nil location means the local time zone. See [Today].
+func TodayAt(clock Clock, loc *time.Location) DoM {
+	if loc == nil {
+		loc = time.Local
+	}
+	return NewDoMFromTime(clockOrDefault(clock).Now().In(loc))
+}
+
+// StartOfDay returns midnight at the start of the day of the given time, in
+// the time's time zone.
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
 // NewDoM creates a new [DoM] based on the month and the day.
 func NewDoM(month time.Month, day int) DoM {
 	return DoM{
@@ -101,8 +439,9 @@ func NewDoM(month time.Month, day int) DoM {
 	}
 }
 
-// LoadCache loads the cached names from ~/.cache/namnsdag/latest.json, or the
-// equivalent in other OS's cache directories (eg. %LOCALAPPDATA%).
+// LoadCache loads the cached names from the file given by [CacheFile], such
+// as ~/.cache/namnsdag/cache@v3.json, or the equivalent in other OS's cache
+// directories (eg. %LOCALAPPDATA%).
 //
 // It will return nil if there is no cache or if the cache is outdated.
 func LoadCache() (Cache, error) {
@@ -110,44 +449,141 @@ func LoadCache() (Cache, error) {
 	if err != nil {
 		return Cache{}, fmt.Errorf("get cache file path: %w", err)
 	}
-	fileBytes, err := os.ReadFile(path)
+	return LoadCacheFile(path)
+}
+
+// LoadCacheFile loads the cached names from a given file. An empty cache is
+// returned if the file does not exist.
+func LoadCacheFile(path string) (Cache, error) {
+	file, err := os.Open(path)
 	if os.IsNotExist(err) {
 		return Cache{}, nil
 	} else if err != nil {
 		return Cache{}, err
 	}
+	defer file.Close()
+	return ReadCache(file)
+}
+
+// ReadCache decodes a cache, as written by [WriteCache], from a reader. The
+// content may optionally be gzip compressed.
+//
+// Returns an error wrapping [ErrCacheCorrupt] if the cache could not be
+// decoded or if its checksum does not match its names. Caches without a
+// checksum, such as those written by older versions, are not verified.
+//
+// Caches with a newer [Cache.SchemaVersion] return an error wrapping
+// [ErrCacheTooNew], instead of being partially decoded. Caches from versions
+// older than 3 are stored in other files, and are read by [MigrateCache].
+func ReadCache(r io.Reader) (Cache, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, gzipMagic) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return Cache{}, fmt.Errorf("%w: %w", ErrCacheCorrupt, err)
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = br
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return Cache{}, fmt.Errorf("%w: %w", ErrCacheCorrupt, err)
+	}
+	var header struct {
+		SchemaVersion int `json:"schemaVersion"`
+	}
+	if err := json.Unmarshal(b, &header); err != nil {
+		return Cache{}, fmt.Errorf("%w: %w", ErrCacheCorrupt, err)
+	}
+	if header.SchemaVersion > CacheVersion {
+		return Cache{}, fmt.Errorf("%w: schema v%d, but this version only supports up to v%d, please upgrade",
+			ErrCacheTooNew, header.SchemaVersion, CacheVersion)
+	}
 	var cache Cache
-	if err := json.Unmarshal(fileBytes, &cache); err != nil {
+	if err := json.Unmarshal(b, &cache); err != nil {
+		return Cache{}, fmt.Errorf("%w: %w", ErrCacheCorrupt, err)
+	}
+	if cache.Checksum == "" {
+		return cache, nil
+	}
+	sum, err := cache.checksum()
+	if err != nil {
 		return Cache{}, err
 	}
+	if sum != cache.Checksum {
+		return Cache{}, fmt.Errorf("%w: checksum mismatch", ErrCacheCorrupt)
+	}
 	return cache, nil
 }
 
-// SaveCache writes the cached names to ~/.cache/namnsdag/latest.json, or the
-// equivalent in other OS's cache directories (eg. %LOCALAPPDATA%).
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// SaveCache writes the cached names to the file given by [CacheFile], such
+// as ~/.cache/namnsdag/cache@v3.json, or the equivalent in other OS's cache
+// directories (eg. %LOCALAPPDATA%).
+//
+// The cache's [Cache.UpdatedAt] is used to detect the cache as outdated when
+// loading the cached names, so it should be set to when the names were
+// fetched, such as using the time from a [Clock].
 //
-// Today's year, month, and day are used to automatically detect the cache as
-// outdated when loading the cached names.
+// The file is gzip compressed if [CompressCache] is set.
 func SaveCache(cache Cache) error {
 	path, err := CacheFile()
 	if err != nil {
 		return fmt.Errorf("get cache file path: %w", err)
 	}
+	return SaveCacheFile(path, cache)
+}
+
+// SaveCacheFile writes the cached names to a given file. The file is gzip
+// compressed if [CompressCache] is set. Nothing is written if
+// [ReadOnlyCache] is set. See [SaveCache] about [Cache.UpdatedAt].
+func SaveCacheFile(path string, cache Cache) error {
+	if ReadOnlyCache {
+		return nil
+	}
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0700); err != nil {
 		return err
 	}
-	file, err := os.Create(path)
+	// Writing to a temporary file first, so a failed write never leaves a
+	// partially written cache file behind.
+	file, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
 	if err != nil {
 		return err
 	}
+	defer os.Remove(file.Name())
 	defer file.Close()
 
-	if cache.UpdatedAt == (time.Time{}) {
-		cache.UpdatedAt = time.Now()
+	if CompressCache {
+		gz := gzip.NewWriter(file)
+		if err := WriteCache(gz, cache); err != nil {
+			return err
+		}
+		if err := gz.Close(); err != nil {
+			return err
+		}
+	} else if err := WriteCache(file, cache); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
 	}
+	return os.Rename(file.Name(), path)
+}
 
-	enc := json.NewEncoder(file)
+// WriteCache encodes a cache to a writer, in the same format as used by
+// [SaveCache]. The cache's checksum is updated before writing.
+func WriteCache(w io.Writer, cache Cache) error {
+	sum, err := cache.checksum()
+	if err != nil {
+		return err
+	}
+	cache.Checksum = sum
+	cache.SchemaVersion = CacheVersion
+	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
 	return enc.Encode(cache)
 }
@@ -155,6 +591,9 @@ func SaveCache(cache Cache) error {
 // ClearCache will remove the cached names, if any. Returns
 // ErrCacheAlreadyCleared if no cache existed.
 func ClearCache() error {
+	if ReadOnlyCache {
+		return ErrCacheReadOnly
+	}
 	path, err := CacheFile()
 	if err != nil {
 		return fmt.Errorf("get cache file path: %w", err)
@@ -172,17 +611,67 @@ func CacheFile() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(dir, "cache@v3.json"), nil
+	return filepath.Join(dir, fmt.Sprintf("cache@v%d.json", CacheVersion)), nil
+}
+
+// DatasetCacheFile returns the path to the cache file of a versioned dataset
+// snapshot. See [DatasetURL].
+func DatasetCacheFile(version string) (string, error) {
+	if !isValidFileName(version) {
+		return "", fmt.Errorf("invalid dataset version: %q", version)
+	}
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "datasets", fmt.Sprintf("%s@v%d.json", version, CacheVersion)), nil
+}
+
+// ThemeDaysCacheFile returns the path to the cache file of the theme days
+// dataset, which is a [Dataset] where all names are of type [TypeThemeDay],
+// used instead of the built-in [ThemeDays] when configured.
+func ThemeDaysCacheFile() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fmt.Sprintf("theme-days@v%d.json", CacheVersion)), nil
+}
+
+// ProfileCacheFile returns the path to the cache file of a named profile,
+// such as "work", so that profiles using different sources don't overwrite
+// each other's cached names.
+func ProfileCacheFile(profile string) (string, error) {
+	if !isValidFileName(profile) {
+		return "", fmt.Errorf("invalid profile name: %q", profile)
+	}
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "profiles", profile, fmt.Sprintf("cache@v%d.json", CacheVersion)), nil
+}
+
+func isValidFileName(s string) bool {
+	return s != "" && !strings.ContainsAny(s, `/\.`)
 }
 
 func cacheDir() (string, error) {
+	if CacheDir != "" {
+		return CacheDir, nil
+	}
 	dir, err := os.UserCacheDir()
 	if err != nil {
-		dir, err = os.UserHomeDir()
+		home, err := os.UserHomeDir()
 		if err != nil {
 			return "", err
 		}
-		dir = filepath.Join(dir, ".cache")
+		// Such as when %LOCALAPPDATA% is unset in services and SSH sessions
+		if runtime.GOOS == "windows" {
+			dir = filepath.Join(home, "AppData", "Local")
+		} else {
+			dir = filepath.Join(home, ".cache")
+		}
 	}
 	return filepath.Join(dir, "namnsdag"), nil
 }
@@ -0,0 +1,60 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag/namnsdagtest"
+)
+
+func TestReadCacheRoundTrip(t *testing.T) {
+	want := namnsdagtest.Cache(time.Date(2026, time.October, 17, 6, 0, 0, 0, time.UTC), fixtureNames...)
+	var buf bytes.Buffer
+	if err := namnsdag.WriteCache(&buf, want); err != nil {
+		t.Fatalf("write: %s", err)
+	}
+	got, err := namnsdag.ReadCache(&buf)
+	if err != nil {
+		t.Fatalf("read: %s", err)
+	}
+	if got.SchemaVersion != namnsdag.CacheVersion {
+		t.Errorf("want schema v%d, got v%d", namnsdag.CacheVersion, got.SchemaVersion)
+	}
+	if !got.UpdatedAt.Equal(want.UpdatedAt) {
+		t.Errorf("want updated at %s, got %s", want.UpdatedAt, got.UpdatedAt)
+	}
+	assertHasNames(t, got.Names(), fixtureNames)
+}
+
+func TestReadCacheTooNew(t *testing.T) {
+	b := fmt.Sprintf(`{"schemaVersion": %d, "namesPerDay": {}}`, namnsdag.CacheVersion+1)
+	_, err := namnsdag.ReadCache(strings.NewReader(b))
+	if !errors.Is(err, namnsdag.ErrCacheTooNew) {
+		t.Errorf("want %v, got %v", namnsdag.ErrCacheTooNew, err)
+	}
+}
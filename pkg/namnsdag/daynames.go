@@ -0,0 +1,84 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import "time"
+
+// DayNames contains the names celebrated on a given day. A list of DayNames
+// is an ordered alternative to the map of [Cache.NamesPerDay], such as for
+// JSON output, where the keys of a map would be sorted as text instead. Use
+// [Cache.DayNames] and [NamesPerDayFrom] to convert between the two.
+type DayNames struct {
+	Date  DoM    `json:"date"`
+	Names []Name `json:"names"`
+}
+
+// Upcoming returns the next n calendar days, starting with the day of from,
+// together with their names. Days without names are included with an empty
+// list of names.
+// The days wrap around from the 31st of December to the 1st of January, and
+// the 29th of February is only included on leap years.
+//
+// The names are copied, so they can be modified without affecting the
+// cache.
+func Upcoming(cache Cache, from time.Time, n int) []DayNames {
+	if n <= 0 {
+		return nil
+	}
+	start := StartOfDay(from)
+	days := make([]DayNames, n)
+	for i := range days {
+		dom := NewDoMFromTime(start.AddDate(0, 0, i))
+		days[i] = DayNames{
+			Date:  dom,
+			Names: append([]Name{}, cache.NamesPerDay[dom]...),
+		}
+	}
+	return days
+}
+
+// DayNames returns the names of all days that have names, in calendar order.
+// The names are copied, so they can be modified without affecting the cache.
+func (c Cache) DayNames() []DayNames {
+	doms := c.SortedDays()
+	days := make([]DayNames, len(doms))
+	for i, dom := range doms {
+		days[i] = DayNames{
+			Date:  dom,
+			Names: append([]Name(nil), c.NamesPerDay[dom]...),
+		}
+	}
+	return days
+}
+
+// NamesPerDayFrom converts a list of days to the map used in
+// [Cache.NamesPerDay]. The names of days that occur multiple times in the
+// list are merged, and days without names are left out.
+func NamesPerDayFrom(days []DayNames) map[DoM][]Name {
+	namesPerDay := make(map[DoM][]Name, len(days))
+	for _, day := range days {
+		if len(day.Names) == 0 {
+			continue
+		}
+		namesPerDay[day.Date] = append(namesPerDay[day.Date], day.Names...)
+	}
+	return namesPerDay
+}
@@ -0,0 +1,123 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag/namnsdagtest"
+)
+
+func TestUpcoming(t *testing.T) {
+	cache := namnsdagtest.Cache(time.Now(),
+		namnsdagtest.Name("Sylvester", time.December, 31),
+		namnsdagtest.Name("Svea", time.January, 1),
+		namnsdagtest.Name("Skottdagen", time.February, 29),
+		namnsdagtest.Name("Albin", time.March, 1),
+	)
+	dom := func(month time.Month, day int) namnsdag.DoM {
+		return namnsdag.DoM{Month: month, Day: day}
+	}
+	tests := []struct {
+		name string
+		from time.Time
+		n    int
+		want []namnsdag.DoM
+	}{
+		{"wraps around new year", time.Date(2026, time.December, 30, 23, 0, 0, 0, time.UTC), 3,
+			[]namnsdag.DoM{dom(time.December, 30), dom(time.December, 31), dom(time.January, 1)}},
+		{"skips Feb 29 in non-leap year", time.Date(2027, time.February, 28, 0, 0, 0, 0, time.UTC), 2,
+			[]namnsdag.DoM{dom(time.February, 28), dom(time.March, 1)}},
+		{"includes Feb 29 in leap year", time.Date(2028, time.February, 28, 0, 0, 0, 0, time.UTC), 3,
+			[]namnsdag.DoM{dom(time.February, 28), dom(time.February, 29), dom(time.March, 1)}},
+		{"single day", time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC), 1,
+			[]namnsdag.DoM{dom(time.December, 31)}},
+		{"no days", time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC), 0, nil},
+		{"negative days", time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC), -1, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			days := namnsdag.Upcoming(cache, tc.from, tc.n)
+			var got []namnsdag.DoM
+			for _, day := range days {
+				got = append(got, day.Date)
+				if want := cache.NamesPerDay[day.Date]; len(day.Names) != len(want) {
+					t.Errorf("want %d names on %s, got %v", len(want), day.Date, day.Names)
+				}
+				if day.Names == nil {
+					t.Errorf("want empty, non-nil names on %s", day.Date)
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("want days %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestUpcomingIsCopy(t *testing.T) {
+	cache := namnsdagtest.Cache(time.Now(), fixtureNames...)
+	days := namnsdag.Upcoming(cache, time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC), 1)
+	days[0].Names[0].Name = "Changed"
+	for _, name := range cache.NamesPerDay[days[0].Date] {
+		if name.Name == "Changed" {
+			t.Errorf("want cache unaffected by changes to upcoming names, got %v", cache.NamesPerDay[days[0].Date])
+		}
+	}
+}
+
+func TestCacheDayNamesRoundTrip(t *testing.T) {
+	cache := namnsdagtest.Cache(time.Now(),
+		namnsdagtest.Name("Svea", time.January, 1),
+		namnsdagtest.Name("Skottdagen", time.February, 29),
+		namnsdagtest.Name("Sylvester", time.December, 31),
+	)
+	days := cache.DayNames()
+	var got []namnsdag.DoM
+	for _, day := range days {
+		got = append(got, day.Date)
+	}
+	want := []namnsdag.DoM{{Month: time.January, Day: 1}, {Month: time.February, Day: 29}, {Month: time.December, Day: 31}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want days in calendar order %v, got %v", want, got)
+	}
+	if namesPerDay := namnsdag.NamesPerDayFrom(days); !reflect.DeepEqual(namesPerDay, cache.NamesPerDay) {
+		t.Errorf("want round trip to give %v, got %v", cache.NamesPerDay, namesPerDay)
+	}
+}
+
+func TestNamesPerDayFromMergesDays(t *testing.T) {
+	leapDay := namnsdag.DoM{Month: time.February, Day: 29}
+	namesPerDay := namnsdag.NamesPerDayFrom([]namnsdag.DayNames{
+		{Date: leapDay, Names: []namnsdag.Name{namnsdagtest.Name("Skottdagen", time.February, 29)}},
+		{Date: namnsdag.DoM{Month: time.March, Day: 1}, Names: []namnsdag.Name{}},
+		{Date: leapDay, Names: []namnsdag.Name{namnsdagtest.Name("Skottdag", time.February, 29)}},
+	})
+	if len(namesPerDay) != 1 {
+		t.Errorf("want only days with names, got %v", namesPerDay)
+	}
+	if got := namesPerDay[leapDay]; len(got) != 2 {
+		t.Errorf("want 2 merged names on %s, got %v", leapDay, got)
+	}
+}
@@ -0,0 +1,102 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import "sort"
+
+// Diff is the difference between two sets of names, as returned by
+// [DiffNames].
+type Diff struct {
+	Added   []Name
+	Removed []Name
+	Moved   []MovedName
+}
+
+// MovedName is a name that has moved from one day to another.
+type MovedName struct {
+	From Name
+	To   Name
+}
+
+// IsEmpty returns true if there are no differences.
+func (d Diff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Moved) == 0
+}
+
+// DiffNames compares an old and a new set of names. Names are compared using
+// [NormalizeName], and a name that exists in both but on different days is
+// reported as moved instead of as added and removed.
+func DiffNames(oldNames, newNames []Name) Diff {
+	oldByName := groupByNormalizedName(oldNames)
+	newByName := groupByNormalizedName(newNames)
+
+	var diff Diff
+	for key, olds := range oldByName {
+		news := newByName[key]
+		removed, added := subtractSameDays(olds, news), subtractSameDays(news, olds)
+		for len(removed) > 0 && len(added) > 0 {
+			diff.Moved = append(diff.Moved, MovedName{From: removed[0], To: added[0]})
+			removed, added = removed[1:], added[1:]
+		}
+		diff.Removed = append(diff.Removed, removed...)
+		diff.Added = append(diff.Added, added...)
+	}
+	for key, news := range newByName {
+		if _, ok := oldByName[key]; !ok {
+			diff.Added = append(diff.Added, news...)
+		}
+	}
+
+	SortNames(diff.Added)
+	SortNames(diff.Removed)
+	sort.Slice(diff.Moved, func(i, j int) bool {
+		return CompareNames(diff.Moved[i].To.Name, diff.Moved[j].To.Name) < 0
+	})
+	return diff
+}
+
+func groupByNormalizedName(names []Name) map[string][]Name {
+	m := make(map[string][]Name)
+	for _, name := range names {
+		key := NormalizeName(name.Name)
+		m[key] = append(m[key], name)
+	}
+	for _, v := range m {
+		SortNames(v)
+	}
+	return m
+}
+
+// subtractSameDays returns the names in a that are not on any of the days of
+// the names in b.
+func subtractSameDays(a, b []Name) []Name {
+	var result []Name
+outer:
+	for _, x := range a {
+		for _, y := range b {
+			if x.DoM() == y.DoM() {
+				continue outer
+			}
+		}
+		result = append(result, x)
+	}
+	return result
+}
@@ -0,0 +1,169 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Holiday is a Swedish public holiday ("röd dag"), official flag day
+// ("allmän flaggdag"), or one of the eves that are de facto holidays, such as
+// Midsummer Eve ("midsommarafton"), on a given date.
+type Holiday struct {
+	Name string    `json:"name"`
+	Date time.Time `json:"date"`
+	// RedDay is true if the holiday is a public holiday, aka "röd dag".
+	RedDay bool `json:"redDay"`
+	// FlagDay is true if the holiday is an official flag day, aka
+	// "allmän flaggdag".
+	FlagDay bool `json:"flagDay"`
+}
+
+// holidayRule is the model of a holiday in the embedded dataset. Holidays
+// without a rule are on the same date every year.
+type holidayRule struct {
+	Name string `json:"name"`
+	// Rule is one of:
+	//   - "": on Date every year.
+	//   - "easter": Offset number of days after Easter Sunday.
+	//   - "friday": the first Friday on or after Date.
+	//   - "saturday": the first Saturday on or after Date.
+	//   - "election": the day of the Swedish general election, only on
+	//     election years.
+	Rule    string `json:"rule"`
+	Date    DoM    `json:"date"`
+	Offset  int    `json:"offset"`
+	RedDay  bool   `json:"redDay"`
+	FlagDay bool   `json:"flagDay"`
+}
+
+//go:embed holidays.json
+var holidaysJSON []byte
+
+var holidayRules = func() []holidayRule {
+	var rules []holidayRule
+	if err := json.Unmarshal(holidaysJSON, &rules); err != nil {
+		panic(fmt.Sprintf("parse embedded holidays: %s", err))
+	}
+	return rules
+}()
+
+// Holidays returns all Swedish public holidays, official flag days, and de
+// facto holidays of a given year, sorted by date. Sundays are not included, even though they are
+// also "röda dagar".
+func Holidays(year int) []Holiday {
+	var holidays []Holiday
+	for _, rule := range holidayRules {
+		date, ok := rule.dateIn(year)
+		if !ok {
+			continue
+		}
+		holidays = append(holidays, Holiday{
+			Name:    rule.Name,
+			Date:    date,
+			RedDay:  rule.RedDay,
+			FlagDay: rule.FlagDay,
+		})
+	}
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+	return holidays
+}
+
+// HolidaysOn returns the Swedish public holidays and official flag days on the
+// date of the given time, if any.
+func HolidaysOn(t time.Time) []Holiday {
+	year, month, day := t.Date()
+	var holidays []Holiday
+	for _, holiday := range Holidays(year) {
+		if holiday.Date.Month() == month && holiday.Date.Day() == day {
+			holidays = append(holidays, holiday)
+		}
+	}
+	return holidays
+}
+
+func (r holidayRule) dateIn(year int) (time.Time, bool) {
+	switch r.Rule {
+	case "":
+		return date(year, r.Date.Month, r.Date.Day), true
+	case "easter":
+		return easterSunday(year).AddDate(0, 0, r.Offset), true
+	case "friday":
+		return weekdayOnOrAfter(date(year, r.Date.Month, r.Date.Day), time.Friday), true
+	case "saturday":
+		return weekdayOnOrAfter(date(year, r.Date.Month, r.Date.Day), time.Saturday), true
+	case "election":
+		return electionDay(year)
+	default:
+		return time.Time{}, false
+	}
+}
+
+// easterSunday calculates the date of Easter Sunday in the Gregorian
+// calendar, using the anonymous Gregorian algorithm.
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
+
+// electionDay returns the day of the Swedish general election, which is held
+// every four years since 1994. It is on the second Sunday of September since
+// 2014, and was on the third Sunday of September before that.
+func electionDay(year int) (time.Time, bool) {
+	if year < 1994 || year%4 != 2 {
+		return time.Time{}, false
+	}
+	firstSunday := date(year, time.September, 1)
+	firstSunday = firstSunday.AddDate(0, 0, int(time.Sunday-firstSunday.Weekday()+7)%7)
+	if year >= 2014 {
+		return firstSunday.AddDate(0, 0, 7), true
+	}
+	return firstSunday.AddDate(0, 0, 14), true
+}
+
+// weekdayOnOrAfter returns the first date on or after d that falls on the
+// weekday.
+func weekdayOnOrAfter(d time.Time, weekday time.Weekday) time.Time {
+	return d.AddDate(0, 0, int(weekday-d.Weekday()+7)%7)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
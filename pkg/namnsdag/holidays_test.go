@@ -0,0 +1,102 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+)
+
+func findHoliday(t *testing.T, year int, name string) time.Time {
+	t.Helper()
+	for _, holiday := range namnsdag.Holidays(year) {
+		if holiday.Name == name {
+			return holiday.Date
+		}
+	}
+	t.Fatalf("want %s in %d, not found", name, year)
+	return time.Time{}
+}
+
+func TestHolidaysKnownDates(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Långfredagen", "2024-03-29"},
+		{"Påskdagen", "2024-03-31"},
+		{"Annandag påsk", "2024-04-01"},
+		{"Kristi himmelsfärdsdag", "2024-05-09"},
+		{"Midsommarafton", "2024-06-21"},
+		{"Midsommardagen", "2024-06-22"},
+		{"Alla helgons dag", "2024-11-02"},
+		{"Påskdagen", "2025-04-20"},
+		{"Pingstdagen", "2025-06-08"},
+		{"Midsommarafton", "2025-06-20"},
+		{"Midsommarafton", "2026-06-19"},
+		{"Valdagen", "2026-09-13"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name+" "+tc.want, func(t *testing.T) {
+			want, err := time.Parse(time.DateOnly, tc.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := findHoliday(t, want.Year(), tc.name); !got.Equal(want) {
+				t.Errorf("want %s, got %s", tc.want, got.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+func TestHolidaysMidsummerEve(t *testing.T) {
+	for year := 2000; year <= 2050; year++ {
+		eve := findHoliday(t, year, "Midsommarafton")
+		if eve.Weekday() != time.Friday || eve.Month() != time.June || eve.Day() < 19 || eve.Day() > 25 {
+			t.Errorf("want Midsommarafton %d on the Friday between June 19-25, got %s (%s)",
+				year, eve.Format(time.DateOnly), eve.Weekday())
+		}
+		if day := findHoliday(t, year, "Midsommardagen"); !day.Equal(eve.AddDate(0, 0, 1)) {
+			t.Errorf("want Midsommardagen %d the day after %s, got %s",
+				year, eve.Format(time.DateOnly), day.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestHolidaysNoElectionOnOtherYears(t *testing.T) {
+	for _, holiday := range namnsdag.Holidays(2025) {
+		if holiday.Name == "Valdagen" {
+			t.Errorf("want no Valdagen in 2025, got %s", holiday.Date.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestHolidaysOn(t *testing.T) {
+	holidays := namnsdag.HolidaysOn(time.Date(2025, time.April, 20, 15, 0, 0, 0, time.UTC))
+	if len(holidays) != 1 || holidays[0].Name != "Påskdagen" || !holidays[0].RedDay || !holidays[0].FlagDay {
+		t.Errorf("want only Påskdagen as a red and flag day, got %v", holidays)
+	}
+	if holidays := namnsdag.HolidaysOn(time.Date(2025, time.April, 22, 0, 0, 0, 0, time.UTC)); len(holidays) != 0 {
+		t.Errorf("want no holidays, got %v", holidays)
+	}
+}
@@ -0,0 +1,122 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// icalUIDDomain is the domain used in the UIDs of calendar events, to make
+// them globally unique.
+const icalUIDDomain = "namnsdag.jillejr.github.com"
+
+// ICalEvent is a yearly recurring all-day calendar event of a namnsdag.
+type ICalEvent struct {
+	// UID is a stable identifier of the event, so that writing the same
+	// event again updates it instead of creating a duplicate.
+	UID     string
+	Summary string
+	DoM     DoM
+}
+
+// NewDayEvent creates a calendar event of all names on a given day.
+func NewDayEvent(dom DoM, names []Name) ICalEvent {
+	var titles []string
+	for _, name := range names {
+		titles = append(titles, name.Name)
+	}
+	return ICalEvent{
+		UID:     fmt.Sprintf("namnsdag-%s@%s", dom, icalUIDDomain),
+		Summary: "Namnsdag: " + strings.Join(titles, ", "),
+		DoM:     dom,
+	}
+}
+
+// NewNameEvent creates a calendar event of a single name, with an optional
+// description of who carries the name, such as "Anna Svensson".
+func NewNameEvent(name Name, who string) ICalEvent {
+	summary := "Namnsdag: " + name.Name
+	if who != "" && who != name.Name {
+		summary += " (" + who + ")"
+	}
+	slug := strings.ReplaceAll(NormalizeName(name.Name), " ", "-")
+	if who != "" {
+		slug += "-" + strings.ReplaceAll(NormalizeName(who), " ", "-")
+	}
+	return ICalEvent{
+		UID:     fmt.Sprintf("namnsdag-%s-%s@%s", name.DoM(), slug, icalUIDDomain),
+		Summary: summary,
+		DoM:     name.DoM(),
+	}
+}
+
+// WriteICalendar writes the events as an iCalendar file, as defined in
+// RFC 5545. The stamp is when the file is created, such as the time from a
+// [Clock], and is written as the DTSTAMP of all events.
+func WriteICalendar(w io.Writer, stamp time.Time, events ...ICalEvent) error {
+	var sb strings.Builder
+	sb.WriteString("BEGIN:VCALENDAR\r\n")
+	sb.WriteString("VERSION:2.0\r\n")
+	sb.WriteString("PRODID:-//jilleJr//namnsdag//SV\r\n")
+	dtstamp := stamp.UTC().Format("20060102T150405Z")
+	for _, e := range events {
+		// Using a leap year to allow the 29th of February
+		start := time.Date(2000, e.DoM.Month, e.DoM.Day, 0, 0, 0, 0, time.UTC)
+		sb.WriteString("BEGIN:VEVENT\r\n")
+		writeICalLine(&sb, "UID:"+e.UID)
+		sb.WriteString("DTSTAMP:" + dtstamp + "\r\n")
+		sb.WriteString("DTSTART;VALUE=DATE:" + start.Format("20060102") + "\r\n")
+		sb.WriteString("DTEND;VALUE=DATE:" + start.AddDate(0, 0, 1).Format("20060102") + "\r\n")
+		sb.WriteString("RRULE:FREQ=YEARLY\r\n")
+		writeICalLine(&sb, "SUMMARY:"+escapeICalText(e.Summary))
+		sb.WriteString("TRANSP:TRANSPARENT\r\n")
+		sb.WriteString("END:VEVENT\r\n")
+	}
+	sb.WriteString("END:VCALENDAR\r\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func escapeICalText(s string) string {
+	return icalTextEscaper.Replace(s)
+}
+
+// writeICalLine writes a content line, folded to at most 75 octets per line
+// without splitting any UTF-8 characters.
+func writeICalLine(sb *strings.Builder, line string) {
+	maxLen := 75
+	for len(line) > maxLen {
+		cut := maxLen
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		sb.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// The continuation lines start with a space
+		maxLen = 74
+	}
+	sb.WriteString(line + "\r\n")
+}
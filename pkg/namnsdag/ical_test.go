@@ -0,0 +1,42 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+)
+
+func TestWriteICalendarStamp(t *testing.T) {
+	stamp := time.Date(2026, time.October, 17, 6, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	var buf bytes.Buffer
+	event := namnsdag.NewNameEvent(fixtureNames[0], "")
+	if err := namnsdag.WriteICalendar(&buf, stamp, event); err != nil {
+		t.Fatal(err)
+	}
+	if want := "DTSTAMP:20261017T040000Z\r\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("want %q in:\n%s", want, buf.String())
+	}
+}
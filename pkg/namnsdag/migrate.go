@@ -0,0 +1,138 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrNoLegacyCache is returned from [MigrateCache] when there are no cache
+// files from older versions to migrate from.
+var ErrNoLegacyCache = errors.New("no cache from older versions found")
+
+// legacyCacheFileNames are the names of the cache files written by older
+// versions of namnsdag, in the order they are migrated: latest.json, from
+// before the cache file was versioned, and cache@v2.json. Both have the
+// format of [legacyCache].
+var legacyCacheFileNames = []string{"latest.json", "cache@v2.json"}
+
+// LegacyCacheFiles returns the paths to any existing cache files written by
+// older versions of namnsdag, such as cache@v2.json.
+func LegacyCacheFiles() ([]string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, name := range legacyCacheFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// MigrateCache reads all cache files written by older versions of namnsdag
+// and saves their names using [SaveCache]. The old files are left untouched.
+//
+// The migrated cache has no ETag, and keeps the UpdatedAt of the old file,
+// or its modification time if unset, so it is refreshed once that is
+// outdated, the same as any other cache. See [Cache.IsOutdated]. Returns
+// [ErrNoLegacyCache] if there was nothing to migrate.
+//
+// The old files are never removed, so callers that migrate automatically
+// should record that the migration has been attempted, to not migrate the
+// same files again after the cache has been wiped.
+func MigrateCache() (Cache, error) {
+	paths, err := LegacyCacheFiles()
+	if err != nil {
+		return Cache{}, fmt.Errorf("find old cache files: %w", err)
+	}
+	var cache Cache
+	for _, path := range paths {
+		legacy, err := readLegacyCacheFile(path)
+		if err != nil {
+			return Cache{}, fmt.Errorf("read old cache file %q: %w", path, err)
+		}
+		cache.AddNames(legacy.names())
+		if legacy.UpdatedAt.After(cache.UpdatedAt) {
+			cache.UpdatedAt = legacy.UpdatedAt
+		}
+	}
+	if len(cache.NamesPerDay) == 0 {
+		return Cache{}, ErrNoLegacyCache
+	}
+	if err := SaveCache(cache); err != nil {
+		return Cache{}, fmt.Errorf("save migrated cache: %w", err)
+	}
+	return cache, nil
+}
+
+// legacyCache is the cache format written by namnsdag v2 and earlier. It has
+// the same layout as [Cache], but its names also had a URL field, which no
+// longer exists on [https://dagensnamnsdag.nu], and is therefore ignored.
+type legacyCache struct {
+	UpdatedAt   time.Time      `json:"updatedAt"`
+	NamesPerDay map[DoM][]Name `json:"namesPerDay"`
+}
+
+func readLegacyCacheFile(path string) (legacyCache, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return legacyCache{}, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return legacyCache{}, err
+	}
+	var legacy legacyCache
+	if err := json.Unmarshal(b, &legacy); err != nil {
+		return legacyCache{}, err
+	}
+	if legacy.UpdatedAt.IsZero() {
+		legacy.UpdatedAt = stat.ModTime()
+	}
+	return legacy, nil
+}
+
+// names returns the valid names of the legacy cache, where names without a
+// date get the date of the day they were stored under.
+func (c legacyCache) names() []Name {
+	var names []Name
+	for dom, dayNames := range c.NamesPerDay {
+		for _, name := range dayNames {
+			if name.Month == 0 && name.Day == 0 {
+				name.Month, name.Day = dom.Month, dom.Day
+			}
+			if name.Validate() == nil {
+				names = append(names, name)
+			}
+		}
+	}
+	SortNames(names)
+	return names
+}
@@ -0,0 +1,113 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag/namnsdagtest"
+)
+
+// useCacheDir makes the cache be read from and written to a new temporary
+// directory, with copies of the given files from testdata/migrate.
+func useCacheDir(t *testing.T, fixtures ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	old := namnsdag.CacheDir
+	namnsdag.CacheDir = dir
+	t.Cleanup(func() { namnsdag.CacheDir = old })
+	for _, name := range fixtures {
+		b, err := os.ReadFile(filepath.Join("testdata", "migrate", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestMigrateCacheV2(t *testing.T) {
+	useCacheDir(t, "cache@v2.json")
+
+	cache, err := namnsdag.MigrateCache()
+	if err != nil {
+		t.Fatalf("migrate: %s", err)
+	}
+	want := []namnsdag.Name{
+		namnsdagtest.Name("Henrik", time.October, 17),
+		namnsdagtest.UnofficialName("Henrika", time.October, 17),
+		namnsdagtest.Name("Skottdagen", time.February, 29),
+	}
+	names := cache.Names()
+	assertHasNames(t, names, want)
+	if len(names) != len(want) {
+		t.Errorf("want %d valid names, got %v", len(want), names)
+	}
+	if want := time.Date(2023, time.May, 1, 6, 0, 0, 0, time.UTC); !cache.UpdatedAt.Equal(want) {
+		t.Errorf("want updated at %s, got %s", want, cache.UpdatedAt)
+	}
+	if cache.ETag != "" {
+		t.Errorf("want no ETag, got %q", cache.ETag)
+	}
+
+	saved, err := namnsdag.LoadCache()
+	if err != nil {
+		t.Fatalf("load migrated cache: %s", err)
+	}
+	assertHasNames(t, saved.Names(), want)
+}
+
+func TestMigrateCacheIgnoresUnknownFiles(t *testing.T) {
+	dir := useCacheDir(t)
+	for _, name := range []string{"cache@v1.json", "2023-10-18.json"} {
+		b := []byte(`{"namesPerDay":{"10-18":[{"slug":"sten","title":"Sten","day":18,"month":10,"type":"OFFICIAL"}]}}`)
+		if err := os.WriteFile(filepath.Join(dir, name), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := namnsdag.MigrateCache(); !errors.Is(err, namnsdag.ErrNoLegacyCache) {
+		t.Errorf("want %v, got %v", namnsdag.ErrNoLegacyCache, err)
+	}
+}
+
+func TestMigrateCacheMergesOldFiles(t *testing.T) {
+	useCacheDir(t, "latest.json", "cache@v2.json")
+
+	cache, err := namnsdag.MigrateCache()
+	if err != nil {
+		t.Fatalf("migrate: %s", err)
+	}
+	assertHasNames(t, cache.Names(), []namnsdag.Name{
+		namnsdagtest.Name("Sten", time.October, 18),
+		namnsdagtest.Name("Henrik", time.October, 17),
+	})
+	if want := time.Date(2023, time.May, 1, 6, 0, 0, 0, time.UTC); !cache.UpdatedAt.Equal(want) {
+		t.Errorf("want updated at %s of the newest file, got %s", want, cache.UpdatedAt)
+	}
+}
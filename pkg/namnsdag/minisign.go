@@ -0,0 +1,123 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// ErrInvalidSignature is returned when a signature does not match the signed
+// content or the public key.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+const (
+	minisignAlgEd25519        = "Ed"
+	minisignAlgEd25519Blake2b = "ED"
+	minisignTrustedPrefix     = "trusted comment: "
+)
+
+// MinisignPublicKey is a public key used to verify signatures created by
+// minisign. See [https://jedisct1.github.io/minisign/].
+type MinisignPublicKey struct {
+	keyID [8]byte
+	key   ed25519.PublicKey
+}
+
+// ParseMinisignPublicKey parses a minisign public key. Either the bare
+// base64-encoded key, such as "RWQf6LRCGA9i53mlYecO4IzT51TGPpvWucNSCh1CBM0QTaLn73Y7GFO3",
+// or the full content of a minisign public key file is accepted.
+func ParseMinisignPublicKey(s string) (MinisignPublicKey, error) {
+	lines := nonEmptyLines(s)
+	if len(lines) == 0 {
+		return MinisignPublicKey{}, errors.New("empty minisign public key")
+	}
+	b, err := base64.StdEncoding.DecodeString(lines[len(lines)-1])
+	if err != nil {
+		return MinisignPublicKey{}, fmt.Errorf("decode minisign public key: %w", err)
+	}
+	if len(b) != 2+8+ed25519.PublicKeySize || string(b[:2]) != minisignAlgEd25519 {
+		return MinisignPublicKey{}, errors.New("invalid minisign public key")
+	}
+	var pk MinisignPublicKey
+	copy(pk.keyID[:], b[2:10])
+	pk.key = ed25519.PublicKey(b[10:])
+	return pk, nil
+}
+
+// Verify checks that the minisign signature, as the content of a .minisig
+// file, is valid for the message. Both legacy and pre-hashed signatures are
+// supported. Returns an error wrapping [ErrInvalidSignature] if not.
+func (pk MinisignPublicKey) Verify(message, signature []byte) error {
+	lines := nonEmptyLines(string(signature))
+	if len(lines) != 4 {
+		return fmt.Errorf("%w: expected 4 lines in minisign signature, got %d", ErrInvalidSignature, len(lines))
+	}
+	sig, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(sig) != 2+8+ed25519.SignatureSize {
+		return fmt.Errorf("%w: malformed signature", ErrInvalidSignature)
+	}
+	alg, keyID, sig := string(sig[:2]), sig[2:10], sig[10:]
+	if !bytes.Equal(keyID, pk.keyID[:]) {
+		return fmt.Errorf("%w: signed with another key", ErrInvalidSignature)
+	}
+	switch alg {
+	case minisignAlgEd25519:
+	case minisignAlgEd25519Blake2b:
+		hash := blake2b.Sum512(message)
+		message = hash[:]
+	default:
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidSignature, alg)
+	}
+	if !ed25519.Verify(pk.key, message, sig) {
+		return fmt.Errorf("%w: signature does not match content", ErrInvalidSignature)
+	}
+
+	trustedComment, ok := strings.CutPrefix(lines[2], minisignTrustedPrefix)
+	if !ok {
+		return fmt.Errorf("%w: missing trusted comment", ErrInvalidSignature)
+	}
+	globalSig, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: malformed global signature", ErrInvalidSignature)
+	}
+	if !ed25519.Verify(pk.key, append(append([]byte(nil), sig...), trustedComment...), globalSig) {
+		return fmt.Errorf("%w: trusted comment has been tampered with", ErrInvalidSignature)
+	}
+	return nil
+}
+
+func nonEmptyLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,90 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidNameURL is returned from [Name.CanonicalURL] when no valid URL
+// can be constructed for a name.
+var ErrInvalidNameURL = errors.New("invalid name URL")
+
+// namePagePath is the path of the website's pages for a single name, which
+// is followed by the name's slug.
+const namePagePath = "/namn/"
+
+// CanonicalURL returns the URL of the name's page on the
+// [https://dagensnamnsdag.nu] website, such as
+// "https://dagensnamnsdag.nu/namn/gosta".
+//
+// If the deprecated [Name.URL] is set, such as from an older cache, then it is
+// used instead, resolved against [URL] if it is relative. Otherwise the URL is
+// built from the [Name.Slug], or from a slug of the name if the slug is empty.
+func (n Name) CanonicalURL() (string, error) {
+	if n.TypeOfName.IsThemeDay() {
+		return "", fmt.Errorf("%w: theme day %q has no page on the website", ErrInvalidNameURL, n.Name)
+	}
+	base, err := url.Parse(URL)
+	if err != nil {
+		return "", fmt.Errorf("%w: parse website URL: %v", ErrInvalidNameURL, err)
+	}
+	if n.URL != "" {
+		u, err := url.Parse(n.URL)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidNameURL, err)
+		}
+		u = base.ResolveReference(u)
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return "", fmt.Errorf("%w: %q: must be an HTTP or HTTPS URL", ErrInvalidNameURL, n.URL)
+		}
+		return u.String(), nil
+	}
+	slug := n.Slug
+	if slug == "" {
+		if strings.TrimSpace(n.Name) == "" {
+			return "", fmt.Errorf("%w: %v", ErrInvalidNameURL, ErrNameWasEmpty)
+		}
+		slug = slugify(n.Name)
+	}
+	if !validSlug(slug) {
+		return "", fmt.Errorf("%w: slug %q: must only contain lowercase letters, digits, and dashes", ErrInvalidNameURL, slug)
+	}
+	return base.ResolveReference(&url.URL{Path: namePagePath + slug}).String(), nil
+}
+
+// validSlug reports whether the slug is safe to use as a single URL path
+// segment, such as "gosta" or "anna-lena".
+func validSlug(slug string) bool {
+	if slug == "" || strings.HasPrefix(slug, "-") || strings.HasSuffix(slug, "-") {
+		return false
+	}
+	for _, r := range slug {
+		if r != '-' && !unicode.IsDigit(r) && !(unicode.IsLetter(r) && !unicode.IsUpper(r)) {
+			return false
+		}
+	}
+	return true
+}
@@ -26,17 +26,26 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"sort"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
 )
 
 var (
 	// URL is the HTTP URL of the website to find data from.
 	URL = "https://dagensnamnsdag.nu/namnsdagar"
 
+	// DefaultUserAgent is the HTTP User-Agent header sent when fetching,
+	// unless overridden via [Request.UserAgent]. It includes the [Version]
+	// of this package.
+	DefaultUserAgent = "namnsdag-go/" + Version + " (+https://github.com/jilleJr/namnsdag)"
+
 	// ErrHTTPNotModified is returned from [Fetch] when the server responded
 	// with status "304 not modified", which means that the etag matched
 	// and our local cache is up to date.
@@ -53,10 +62,16 @@ type Name struct {
 	Month      time.Month `json:"month"`
 	TypeOfName Type       `json:"type"`
 
+	// Variants are other spellings of the same name, such as "Michael" for
+	// "Mikael". See [SplitCombinedNames].
+	Variants []string `json:"variants,omitempty"`
+
 	// Deprecated: This field no longer exists on [https://dagensnamnsdag.nu]
 	URL string `json:"-"`
-	// Deprecated: This field no longer exists on [https://dagensnamnsdag.nu]
-	Gender Gender `json:"-"`
+	// Gender of the name, if known. This field no longer exists on
+	// [https://dagensnamnsdag.nu], so it is only set by sources that still
+	// provide it, such as datasets.
+	Gender Gender `json:"gender,omitempty"`
 }
 
 // DoM returns this name's Day-of-Month.
@@ -64,16 +79,50 @@ func (n Name) DoM() DoM {
 	return NewDoM(n.Month, n.Day)
 }
 
-// Type is an enum stating what kind of namnsdag-name it is.
+// Type is an enum stating what kind of namnsdag-name it is. Use the
+// predicates such as [Type.IsOfficial] instead of comparing with the
+// constants, as there may be other values from [https://dagensnamnsdag.nu].
 type Type string
 
 // Known values for [Type]. There may be other values from
 // [https://dagensnamnsdag.nu], but these are the ones found so far.
 const (
-	TypeOfficial   Type = "OFFICIAL"
+	// TypeOfficial is used for names in the official Swedish namnsdag
+	// almanac.
+	TypeOfficial Type = "OFFICIAL"
+	// TypeNew is used for newly added names, that are not (yet) part of the
+	// official almanac.
+	TypeNew Type = "NEW_NAME"
+	// TypeUnofficial is used for unofficial names, aka "Bolibompa
+	// namnsdagar", from the calendar of the children's TV show Bolibompa.
 	TypeUnofficial Type = "UNOFFICIAL"
+	// TypeThemeDay is used for Swedish theme days ("temadagar"), such as
+	// "Kanelbullens dag", as returned by [ThemeDays].
+	TypeThemeDay Type = "THEME_DAY"
 )
 
+// IsOfficial reports whether the name is in the official namnsdag almanac.
+func (t Type) IsOfficial() bool {
+	return t == TypeOfficial
+}
+
+// IsNew reports whether the name is newly added, and not (yet) part of the
+// official almanac.
+func (t Type) IsNew() bool {
+	return t == TypeNew
+}
+
+// IsUnofficial reports whether the name is an unofficial name, aka
+// "Bolibompa namnsdag".
+func (t Type) IsUnofficial() bool {
+	return t == TypeUnofficial
+}
+
+// IsThemeDay reports whether it is a theme day ("temadag") instead of a name.
+func (t Type) IsThemeDay() bool {
+	return t == TypeThemeDay
+}
+
 // Gender is an enum stating what gender a namnsdag-name has, if any.
 type Gender string
 
@@ -89,25 +138,161 @@ const (
 // Request is the model used for a [Fetch] of names from [URL].
 type Request struct {
 	ETag string
+
+	// HTTPClient is the client used to send the request. Defaults to
+	// [http.DefaultClient], which respects the HTTP_PROXY, HTTPS_PROXY, and
+	// NO_PROXY environment variables.
+	HTTPClient *http.Client
+
+	// UserAgent is the HTTP User-Agent header to send. Defaults to
+	// [DefaultUserAgent].
+	UserAgent string
+
+	// Strict makes the fetch fail with a [*SchemaDriftError] when the fetched
+	// data lacks expected fields or contains no names, instead of only
+	// reporting it via [Response.SchemaDrift].
+	Strict bool
+
+	// Logger receives debug logs about the fetch, such as which strategy
+	// was used to extract the names. Defaults to [slog.Default].
+	Logger *slog.Logger
+
+	// Clock tells the current time, such as for [NameStatsTable.FetchedAt].
+	// Defaults to [SystemClock].
+	Clock Clock
+}
+
+func (r Request) logger() *slog.Logger {
+	if r.Logger == nil {
+		return slog.Default()
+	}
+	return r.Logger
+}
+
+func (r Request) clock() Clock {
+	return clockOrDefault(r.Clock)
 }
 
 // Response is the data received from a [Fetch] of names from [URL].
 type Response struct {
 	Names []Name
 	ETag  string
+
+	// SchemaDrift is set when the fetched data did not match the expected
+	// schema, but [Request.Strict] was not set.
+	SchemaDrift *SchemaDriftError
 }
 
-// Fetch performs a HTTP GET request and parses the HTML response
-// to extract all names.
+// Fetch performs a HTTP GET request to [URL] and parses the HTML response
+// to extract all names. This is the same as using [WebSource].
 func Fetch(req Request) (Response, error) {
-	data, etag, err := fetchAllNextJSData(req.ETag)
+	return WebSource{}.Fetch(req)
+}
+
+func fetchFromWeb(url string, req Request, required []string) (Response, error) {
+	resp, err := doGet(url, req)
 	if errors.Is(err, ErrHTTPNotModified) {
-		return Response{ETag: etag}, err
+		return Response{ETag: req.ETag}, err
+	}
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return Response{}, fmt.Errorf("parse HTML: %w", err)
+	}
+	log := req.logger()
+	raw, err := findNextJSData(doc, log)
+	if errors.Is(err, errNoNextJSData) {
+		if buildID := findNextJSBuildID(doc); buildID != "" {
+			log.Debug("trying Next.js data route", "buildId", buildID)
+			routeRaw, routeErr := fetchNextJSDataRoute(url, buildID, req)
+			switch {
+			case routeErr == nil:
+				raw, err = routeRaw, nil
+			case raw == nil:
+				err = routeErr
+			default:
+				log.Debug("failed to fetch Next.js data route", "error", routeErr)
+			}
+		}
+		if errors.Is(err, errNoNextJSData) && raw != nil {
+			log.Debug("using data without names from page")
+			err = nil
+		}
+	}
+	if err != nil {
+		return Response{}, err
 	}
+	names, drift, err := parseNextJSData(raw, required)
 	if err != nil {
 		return Response{}, err
 	}
+	if drift != nil && req.Strict {
+		return Response{}, drift
+	}
+	return Response{
+		Names:       names,
+		ETag:        resp.Header.Get("etag"),
+		SchemaDrift: drift,
+	}, nil
+}
+
+// ParseHTML parses a HTML page from the [https://dagensnamnsdag.nu] website
+// to extract all names. This is the parsing done by [Fetch], and can be used
+// to parse pages that have been saved to disk.
+//
+// The names are extracted from the Next.js data embedded in the page, where
+// multiple strategies are tried to find it, in case the website changes.
+// Debug logs about which strategy worked are written to [slog.Default].
+func ParseHTML(r io.Reader) ([]Name, error) {
+	names, _, err := parseHTML(r)
+	return names, err
+}
+
+// ParseHTMLStrict is like [ParseHTML], but returns a [*SchemaDriftError] if
+// the page's data lacks expected fields or contains no names.
+func ParseHTMLStrict(r io.Reader) ([]Name, error) {
+	names, drift, err := parseHTML(r)
+	if err != nil {
+		return nil, err
+	}
+	if drift != nil {
+		return nil, drift
+	}
+	return names, nil
+}
+
+func parseHTML(r io.Reader) ([]Name, *SchemaDriftError, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse HTML: %w", err)
+	}
+	raw, err := findNextJSData(doc, slog.Default())
+	if errors.Is(err, errNoNextJSData) && raw != nil {
+		slog.Debug("using data without names from page")
+	} else if err != nil {
+		return nil, nil, err
+	}
+	return parseNextJSData(raw, requiredNameFields)
+}
+
+func parseNextJSData(raw []byte, required []string) ([]Name, *SchemaDriftError, error) {
+	var data nextJSData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, nil, fmt.Errorf("parse Next.js data: %w", err)
+	}
 	names := data.Props.PageProps.Names
+	if err := validateNames(names); err != nil {
+		return nil, nil, err
+	}
+	names = SplitCombinedNames(names)
+	SortNames(names)
+	return names, checkSchema(raw, required, "props", "pageProps", "names"), nil
+}
+
+func validateNames(names []Name) error {
 	type InvalidName struct {
 		Name
 		Error error
@@ -121,32 +306,27 @@ func Fetch(req Request) (Response, error) {
 			})
 		}
 	}
-	if len(invalidNameDates) > 0 {
-		switch len(invalidNameDates) {
-		case 1:
-			return Response{}, invalidNameDates[0].Error
-		case 2:
-			return Response{}, fmt.Errorf("%w, %w",
-				invalidNameDates[0].Error,
-				invalidNameDates[1].Error)
-		case 3:
-			return Response{}, fmt.Errorf("%w, %w, %w",
-				invalidNameDates[0].Error,
-				invalidNameDates[1].Error,
-				invalidNameDates[2].Error)
-		default:
-			return Response{}, fmt.Errorf("found %d errors, first 3: %w, %w, %w",
-				len(invalidNameDates),
-				invalidNameDates[0].Error,
-				invalidNameDates[1].Error,
-				invalidNameDates[2].Error)
-		}
+	switch len(invalidNameDates) {
+	case 0:
+		return nil
+	case 1:
+		return invalidNameDates[0].Error
+	case 2:
+		return fmt.Errorf("%w, %w",
+			invalidNameDates[0].Error,
+			invalidNameDates[1].Error)
+	case 3:
+		return fmt.Errorf("%w, %w, %w",
+			invalidNameDates[0].Error,
+			invalidNameDates[1].Error,
+			invalidNameDates[2].Error)
+	default:
+		return fmt.Errorf("found %d errors, first 3: %w, %w, %w",
+			len(invalidNameDates),
+			invalidNameDates[0].Error,
+			invalidNameDates[1].Error,
+			invalidNameDates[2].Error)
 	}
-	SortNames(names)
-	return Response{
-		Names: names,
-		ETag:  etag,
-	}, nil
 }
 
 func (n Name) Validate() error {
@@ -157,8 +337,10 @@ func (n Name) Validate() error {
 }
 
 // SortNames will sort a slice of names first by month, then by day, and finally
-// by name, all in ascending order.
+// by name, all in ascending order. Names are compared using Swedish collation,
+// so that Å, Ä, and Ö are sorted after Z.
 func SortNames(names []Name) {
+	coll := newCollator()
 	sort.Slice(names, func(i, j int) bool {
 		diffMonths := names[i].Month != names[j].Month
 		if diffMonths {
@@ -168,59 +350,75 @@ func SortNames(names []Name) {
 		if diffDays {
 			return names[i].Day < names[j].Day
 		}
-		return names[i].Name < names[j].Name
+		return coll.CompareString(names[i].Name, names[j].Name) < 0
 	})
 }
 
-type nextJSData struct {
-	Props struct {
-		PageProps struct {
-			Names []Name `json:"names"`
-		} `json:"pageProps"`
-	} `json:"props"`
+// SortNamesByName will sort a slice of names first by name, and then by month
+// and day, all in ascending order. Names are compared using Swedish
+// collation, so that Å, Ä, and Ö are sorted after Z.
+func SortNamesByName(names []Name) {
+	coll := newCollator()
+	sort.Slice(names, func(i, j int) bool {
+		if cmp := coll.CompareString(names[i].Name, names[j].Name); cmp != 0 {
+			return cmp < 0
+		}
+		if names[i].Month != names[j].Month {
+			return names[i].Month < names[j].Month
+		}
+		return names[i].Day < names[j].Day
+	})
 }
 
-func fetchAllNextJSData(etag string) (*nextJSData, string, error) {
-	doc, newEtag, err := fetchDocument(etag)
-	if errors.Is(err, ErrHTTPNotModified) {
-		return nil, etag, err
-	}
+// CompareNames compares two names using Swedish collation, where Å, Ä, and Ö
+// are sorted after Z. The result is 0 if a == b, -1 if a < b, and +1 if a > b.
+func CompareNames(a, b string) int {
+	return newCollator().CompareString(a, b)
+}
+
+// newCollator returns a new Swedish collator. A new one is needed per sort,
+// as collators are not safe for concurrent use.
+func newCollator() *collate.Collator {
+	return collate.New(language.Swedish)
+}
+
+// doGet sends a HTTP GET request, and returns an error if the response did
+// not have a 2xx status code. Returns [ErrHTTPNotModified] if the ETag in the
+// request matched.
+func doGet(url string, r Request) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return nil, "", err
-	}
-	q := doc.Find(`script[id="__NEXT_DATA__"]`).First()
-	if len(q.Nodes) == 0 {
-		return nil, "", fmt.Errorf("no <script id='__NEXT_DATA__'> tag found")
+		return nil, err
 	}
-	var data nextJSData
-	if err := json.Unmarshal([]byte(q.Text()), &data); err != nil {
-		return nil, "", fmt.Errorf("parsing JSON in <script id='__NEXT_DATA__'> tag: %w", err)
+	if r.ETag != "" {
+		req.Header.Add("If-None-Match", r.ETag)
 	}
-	return &data, newEtag, nil
+	return do(req, r)
 }
 
-func fetchDocument(etag string) (*goquery.Document, string, error) {
-	req, err := http.NewRequest(http.MethodGet, URL, nil)
-	if err != nil {
-		return nil, "", err
+// do sends a HTTP request using the client and User-Agent of the [Request],
+// and returns an error if the response did not have a 2xx status code.
+func do(req *http.Request, r Request) (*http.Response, error) {
+	if r.UserAgent != "" {
+		req.Header.Set("User-Agent", r.UserAgent)
+	} else {
+		req.Header.Set("User-Agent", DefaultUserAgent)
 	}
-	if etag != "" {
-		req.Header.Add("If-None-Match", etag)
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
-	defer resp.Body.Close()
 	if resp.StatusCode == http.StatusNotModified {
-		return nil, "", ErrHTTPNotModified
+		resp.Body.Close()
+		return nil, ErrHTTPNotModified
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, "", fmt.Errorf("non-2xx status code: %s", resp.Status)
-	}
-	q, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, "", fmt.Errorf("parse HTML: %w", err)
+		resp.Body.Close()
+		return nil, fmt.Errorf("non-2xx status code: %s", resp.Status)
 	}
-	return q, resp.Header.Get("etag"), nil
+	return resp, nil
 }
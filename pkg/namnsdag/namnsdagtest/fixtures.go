@@ -0,0 +1,94 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdagtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Fixture is a raw response recorded using [namnsdag.RecordingTransport].
+type Fixture struct {
+	// Name is the file name of the fixture.
+	Name string
+	Body []byte
+}
+
+// IsJSON returns true if the fixture is a recorded JSON dataset, and false
+// if it is a recorded HTML page.
+func (f Fixture) IsJSON() bool {
+	return strings.HasSuffix(f.Name, ".json")
+}
+
+// LoadFixtures loads all fixtures from a directory, sorted by name, which
+// is also in the order they were recorded. This allows replaying multiple
+// historical snapshots of the website, such as:
+//
+//	fixtures, err := namnsdagtest.LoadFixtures("testdata/fixtures")
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	for _, f := range fixtures {
+//		t.Run(f.Name, func(t *testing.T) {
+//			names, err := namnsdag.ParseHTML(bytes.NewReader(f.Body))
+//			// ...
+//		})
+//	}
+func LoadFixtures(dir string) ([]Fixture, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []Fixture
+	for _, entry := range entries {
+		// Skipping the REUSE license files next to the fixtures
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), ".license") {
+			continue
+		}
+		body, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, Fixture{Name: entry.Name(), Body: body})
+	}
+	sort.Slice(fixtures, func(i, j int) bool {
+		return fixtures[i].Name < fixtures[j].Name
+	})
+	return fixtures, nil
+}
+
+// NewReplayServer starts a HTTP test server that replays the fixture on
+// every request, no matter the path. The server must be closed when done,
+// using its Close method.
+func NewReplayServer(f Fixture) *httptest.Server {
+	contentType := "text/html; charset=utf-8"
+	if f.IsJSON() {
+		contentType = "application/json"
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Write(f.Body)
+	}))
+}
@@ -0,0 +1,222 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package namnsdagtest contains helpers for testing code that uses the
+// namnsdag package, without needing network access.
+package namnsdagtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+)
+
+// Name creates an official name celebrated on the given day.
+func Name(name string, month time.Month, day int) namnsdag.Name {
+	return namnsdag.Name{
+		Slug:       strings.ToLower(name),
+		Name:       name,
+		Month:      month,
+		Day:        day,
+		TypeOfName: namnsdag.TypeOfficial,
+	}
+}
+
+// NewName creates a newly added name celebrated on the given day.
+func NewName(name string, month time.Month, day int) namnsdag.Name {
+	n := Name(name, month, day)
+	n.TypeOfName = namnsdag.TypeNew
+	return n
+}
+
+// UnofficialName creates an unofficial name celebrated on the given day.
+func UnofficialName(name string, month time.Month, day int) namnsdag.Name {
+	n := Name(name, month, day)
+	n.TypeOfName = namnsdag.TypeUnofficial
+	return n
+}
+
+// Cache creates a cache containing the given names, updated at the given
+// time.
+func Cache(updatedAt time.Time, names ...namnsdag.Name) namnsdag.Cache {
+	cache := namnsdag.Cache{UpdatedAt: updatedAt}
+	cache.SetNames(names)
+	return cache
+}
+
+// Source is a fake [namnsdag.Source] that returns canned names, and records
+// all requests made to it. It is safe for concurrent use.
+type Source struct {
+	Names []namnsdag.Name
+	ETag  string
+	// Err, if set, is returned from all calls to Fetch.
+	Err error
+
+	mu       sync.Mutex
+	requests []namnsdag.Request
+}
+
+var _ namnsdag.Source = &Source{}
+
+// Fetch implements [namnsdag.Source]. Returns [namnsdag.ErrHTTPNotModified]
+// if the request's ETag matches the source's ETag.
+func (s *Source) Fetch(req namnsdag.Request) (namnsdag.Response, error) {
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	s.mu.Unlock()
+	if s.Err != nil {
+		return namnsdag.Response{}, s.Err
+	}
+	if s.ETag != "" && req.ETag == s.ETag {
+		return namnsdag.Response{ETag: s.ETag}, namnsdag.ErrHTTPNotModified
+	}
+	names := append([]namnsdag.Name(nil), s.Names...)
+	namnsdag.SortNames(names)
+	return namnsdag.Response{Names: names, ETag: s.ETag}, nil
+}
+
+// String implements [namnsdag.Source].
+func (s *Source) String() string {
+	return "namnsdagtest.Source"
+}
+
+// Requests returns all requests made to the source so far.
+func (s *Source) Requests() []namnsdag.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]namnsdag.Request(nil), s.requests...)
+}
+
+// NextJSHTML renders a HTML page with the names inside a
+// <script id="__NEXT_DATA__"> tag, the same way as the
+// [https://dagensnamnsdag.nu] website does.
+func NextJSHTML(names []namnsdag.Name) []byte {
+	var data struct {
+		Props struct {
+			PageProps struct {
+				Names []namnsdag.Name `json:"names"`
+			} `json:"pageProps"`
+		} `json:"props"`
+	}
+	data.Props.PageProps.Names = names
+	// The JSON encoder escapes <, >, and &, so it is safe to embed in HTML
+	b, err := json.Marshal(data)
+	if err != nil {
+		panic(fmt.Sprintf("namnsdagtest: encode names: %s", err))
+	}
+	return []byte(fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head><title>Namnsdagar</title></head>
+<body>
+<script id="__NEXT_DATA__" type="application/json">%s</script>
+</body>
+</html>
+`, b))
+}
+
+// Server is a HTTP test server serving canned names, both as a HTML page in
+// the same way as the [https://dagensnamnsdag.nu] website does, and as a
+// JSON [namnsdag.Dataset].
+type Server struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	names []namnsdag.Name
+	etag  string
+	hits  int
+}
+
+// Paths served by [Server].
+const (
+	PathWeb     = "/namnsdagar"
+	PathDataset = "/names.json"
+)
+
+// NewServer starts a new [Server] serving the given names. The server must be
+// closed when done, using its Close method.
+func NewServer(names ...namnsdag.Name) *Server {
+	s := &Server{}
+	s.SetNames(names...)
+	mux := http.NewServeMux()
+	mux.HandleFunc(PathWeb, func(w http.ResponseWriter, r *http.Request) {
+		s.serve(w, r, "text/html; charset=utf-8", NextJSHTML)
+	})
+	mux.HandleFunc(PathDataset, func(w http.ResponseWriter, r *http.Request) {
+		s.serve(w, r, "application/json", func(names []namnsdag.Name) []byte {
+			b, _ := json.Marshal(namnsdag.Dataset{Names: names})
+			return b
+		})
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// WebSource returns a [namnsdag.WebSource] fetching from this server.
+func (s *Server) WebSource() namnsdag.WebSource {
+	return namnsdag.WebSource{URL: s.URL + PathWeb}
+}
+
+// DatasetSource returns a [namnsdag.DatasetSource] fetching from this server.
+func (s *Server) DatasetSource() namnsdag.DatasetSource {
+	return namnsdag.DatasetSource{URL: s.URL + PathDataset}
+}
+
+// SetNames replaces the names served, which also changes the ETag.
+func (s *Server) SetNames(names ...namnsdag.Name) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.names = append([]namnsdag.Name(nil), names...)
+	s.etag = fmt.Sprintf(`"%d-%d"`, len(names), time.Now().UnixNano())
+}
+
+// ETag returns the ETag of the names currently served.
+func (s *Server) ETag() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.etag
+}
+
+// Hits returns the number of requests made to the server so far.
+func (s *Server) Hits() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hits
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, contentType string, render func([]namnsdag.Name) []byte) {
+	s.mu.Lock()
+	s.hits++
+	names, etag := s.names, s.etag
+	s.mu.Unlock()
+
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(render(names))
+}
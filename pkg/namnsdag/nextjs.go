@@ -0,0 +1,188 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// errNoNextJSData is returned when none of the extraction strategies could
+// find the Next.js data in a page.
+var errNoNextJSData = errors.New("no Next.js data found in page")
+
+type nextJSData struct {
+	Props struct {
+		PageProps struct {
+			Names []Name `json:"names"`
+		} `json:"pageProps"`
+	} `json:"props"`
+}
+
+// nextJSStrategy is a way of extracting the Next.js data, as JSON, from a
+// page. Each strategy returns all candidates found in the page.
+type nextJSStrategy struct {
+	name    string
+	extract func(doc *goquery.Document) [][]byte
+}
+
+// nextJSStrategies are tried in order, where the first one is how the
+// website is structured as of writing, and the rest are fallbacks in case the
+// website is changed.
+var nextJSStrategies = []nextJSStrategy{
+	{name: "script#__NEXT_DATA__", extract: extractNextDataTag},
+	{name: "script[type=application/json]", extract: extractJSONScriptTags},
+	{name: "inline __NEXT_DATA__ assignment", extract: extractInlineNextData},
+}
+
+func extractNextDataTag(doc *goquery.Document) [][]byte {
+	q := doc.Find(`script[id="__NEXT_DATA__"]`).First()
+	if len(q.Nodes) == 0 {
+		return nil
+	}
+	return [][]byte{[]byte(q.Text())}
+}
+
+func extractJSONScriptTags(doc *goquery.Document) [][]byte {
+	var candidates [][]byte
+	doc.Find(`script[type="application/json"]`).Each(func(_ int, s *goquery.Selection) {
+		candidates = append(candidates, []byte(s.Text()))
+	})
+	return candidates
+}
+
+var inlineNextDataPattern = regexp.MustCompile(`__NEXT_DATA__\s*=\s*`)
+
+func extractInlineNextData(doc *goquery.Document) [][]byte {
+	var candidates [][]byte
+	doc.Find("script").Each(func(_ int, s *goquery.Selection) {
+		text := s.Text()
+		loc := inlineNextDataPattern.FindStringIndex(text)
+		if loc == nil {
+			return
+		}
+		// Only decode the first JSON value, ignoring any trailing code.
+		var raw json.RawMessage
+		if err := json.NewDecoder(strings.NewReader(text[loc[1]:])).Decode(&raw); err == nil {
+			candidates = append(candidates, raw)
+		}
+	})
+	return candidates
+}
+
+// findNextJSData tries all extraction strategies in order, and returns the
+// first JSON that contains the names. If no JSON contains the names, then
+// [errNoNextJSData] is returned together with the JSON from the first
+// strategy, if any, so that the schema drift can be diagnosed when nothing
+// better is found.
+func findNextJSData(doc *goquery.Document, log *slog.Logger) ([]byte, error) {
+	var fallback []byte
+	for _, strategy := range nextJSStrategies {
+		candidates := strategy.extract(doc)
+		for _, raw := range candidates {
+			if !json.Valid(raw) {
+				continue
+			}
+			if hasNextJSNames(raw) {
+				log.Debug("found names in page", "strategy", strategy.name)
+				return raw, nil
+			}
+			if fallback == nil {
+				fallback = raw
+			}
+		}
+		log.Debug("found no names in page", "strategy", strategy.name, "candidates", len(candidates))
+	}
+	return fallback, errNoNextJSData
+}
+
+func hasNextJSNames(raw []byte) bool {
+	var data struct {
+		Props struct {
+			PageProps struct {
+				Names json.RawMessage `json:"names"`
+			} `json:"pageProps"`
+		} `json:"props"`
+	}
+	return json.Unmarshal(raw, &data) == nil && len(data.Props.PageProps.Names) > 0
+}
+
+var nextJSBuildIDPattern = regexp.MustCompile(`/_next/static/([^/]+)/_(?:buildManifest|ssgManifest)\.js`)
+
+// findNextJSBuildID returns the Next.js build ID from the page's script
+// tags, or an empty string if not found.
+func findNextJSBuildID(doc *goquery.Document) string {
+	var buildID string
+	doc.Find("script[src]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		src, _ := s.Attr("src")
+		if m := nextJSBuildIDPattern.FindStringSubmatch(src); m != nil {
+			buildID = m[1]
+			return false
+		}
+		return true
+	})
+	return buildID
+}
+
+// fetchNextJSDataRoute fetches the Next.js data from the JSON data route of
+// a page, which is what Next.js itself uses on client-side navigation.
+// The returned JSON is on the same format as the page's __NEXT_DATA__.
+func fetchNextJSDataRoute(pageURL, buildID string, req Request) ([]byte, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	page := strings.TrimSuffix(u.Path, "/")
+	if page == "" {
+		page = "/index"
+	}
+	u.Path = path.Join("/_next/data", buildID, page+".json")
+	u.RawQuery = ""
+	req.ETag = ""
+	resp, err := doGet(u.String(), req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch Next.js data route: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("fetch Next.js data route: %w", err)
+	}
+	if !json.Valid(body) {
+		return nil, errors.New("fetch Next.js data route: response is not JSON")
+	}
+	// The data route only contains the page props.
+	var buf bytes.Buffer
+	buf.WriteString(`{"props":`)
+	buf.Write(bytes.TrimSpace(body))
+	buf.WriteString(`}`)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,55 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import "time"
+
+// NextOccurrence returns midnight at the start of the next date that falls on
+// the day, in the time zone of after. The day of after itself counts, so the
+// result is after's own date when it already falls on the day. The 29th of
+// February only occurs on leap years, and wraps around to the next leap year.
+func (d DoM) NextOccurrence(after time.Time) time.Time {
+	start := StartOfDay(after)
+	// The 29th of February occurs at least once every 8 years.
+	for year := start.Year(); year <= start.Year()+8; year++ {
+		t := time.Date(year, d.Month, d.Day, 0, 0, 0, 0, start.Location())
+		if t.Month() == d.Month && !t.Before(start) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// NextOccurrence returns the date of the next namnsdag of a name, on or after
+// the day of after, in the time zone of after. The name is looked up using
+// [Cache.FindName], so names that occur multiple times in the year return
+// whichever occurrence comes first. Returns false if the name has no
+// namnsdag.
+func (c Cache) NextOccurrence(name string, after time.Time) (time.Time, bool) {
+	var next time.Time
+	for _, n := range c.FindName(name) {
+		t := n.DoM().NextOccurrence(after)
+		if !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	return next, !next.IsZero()
+}
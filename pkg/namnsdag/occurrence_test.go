@@ -0,0 +1,153 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag/namnsdagtest"
+)
+
+func TestDoMNextOccurrence(t *testing.T) {
+	tests := []struct {
+		name  string
+		dom   namnsdag.DoM
+		after time.Time
+		want  time.Time
+	}{
+		{
+			name:  "same day",
+			dom:   namnsdag.DoM{Month: time.October, Day: 17},
+			after: time.Date(2026, time.October, 17, 23, 59, 0, 0, time.UTC),
+			want:  time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "later this year",
+			dom:   namnsdag.DoM{Month: time.October, Day: 18},
+			after: time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC),
+			want:  time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "Dec 31 wraps to Jan 1",
+			dom:   namnsdag.DoM{Month: time.January, Day: 1},
+			after: time.Date(2026, time.December, 31, 12, 0, 0, 0, time.UTC),
+			want:  time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "Dec 31 from Jan 1",
+			dom:   namnsdag.DoM{Month: time.December, Day: 31},
+			after: time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC),
+			want:  time.Date(2027, time.December, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "Feb 29 in non-leap year",
+			dom:   namnsdag.DoM{Month: time.February, Day: 29},
+			after: time.Date(2026, time.February, 28, 12, 0, 0, 0, time.UTC),
+			want:  time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "Feb 29 in leap year",
+			dom:   namnsdag.DoM{Month: time.February, Day: 29},
+			after: time.Date(2028, time.January, 1, 0, 0, 0, 0, time.UTC),
+			want:  time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "Feb 29 after leap day",
+			dom:   namnsdag.DoM{Month: time.February, Day: 29},
+			after: time.Date(2028, time.March, 1, 0, 0, 0, 0, time.UTC),
+			want:  time.Date(2032, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "Feb 29 over century non-leap year",
+			dom:   namnsdag.DoM{Month: time.February, Day: 29},
+			after: time.Date(2096, time.March, 1, 0, 0, 0, 0, time.UTC),
+			want:  time.Date(2104, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "Mar 1 in non-leap year",
+			dom:   namnsdag.DoM{Month: time.March, Day: 1},
+			after: time.Date(2026, time.February, 28, 0, 0, 0, 0, time.UTC),
+			want:  time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.dom.NextOccurrence(tc.after); !got.Equal(tc.want) {
+				t.Errorf("want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDoMNextOccurrenceKeepsTimeZone(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		t.Skipf("load time zone: %s", err)
+	}
+	// 23:30 UTC on Dec 31 is already Jan 1 in Stockholm
+	after := time.Date(2026, time.December, 31, 23, 30, 0, 0, time.UTC).In(stockholm)
+	got := namnsdag.DoM{Month: time.January, Day: 1}.NextOccurrence(after)
+	if want := time.Date(2027, time.January, 1, 0, 0, 0, 0, stockholm); !got.Equal(want) || got.Location() != stockholm {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestCacheNextOccurrence(t *testing.T) {
+	cache := namnsdagtest.Cache(time.Now(),
+		namnsdagtest.Name("Sylvester", time.December, 31),
+		namnsdagtest.Name("Nyårsdagen", time.January, 1),
+		namnsdagtest.Name("Skottdagen", time.February, 29),
+		// Names that occur multiple times in the year.
+		namnsdagtest.Name("Maria", time.March, 25),
+		namnsdagtest.Name("Maria", time.December, 8),
+	)
+	tests := []struct {
+		name   string
+		lookup string
+		after  time.Time
+		want   time.Time
+	}{
+		{"Dec 31 wraps to Jan 1", "Nyårsdagen", time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC), time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"on Dec 31", "Sylvester", time.Date(2026, time.December, 31, 23, 0, 0, 0, time.UTC), time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)},
+		{"Feb 29 in non-leap year", "Skottdagen", time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"first of multiple", "Maria", time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, time.March, 25, 0, 0, 0, 0, time.UTC)},
+		{"second of multiple", "Maria", time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, time.December, 8, 0, 0, 0, 0, time.UTC)},
+		{"multiple across year boundary", "Maria", time.Date(2026, time.December, 9, 0, 0, 0, 0, time.UTC), time.Date(2027, time.March, 25, 0, 0, 0, 0, time.UTC)},
+		{"normalized name", "maria", time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, time.December, 8, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := cache.NextOccurrence(tc.lookup, tc.after)
+			if !ok {
+				t.Fatalf("want next occurrence of %s", tc.lookup)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("want %s, got %s", tc.want, got)
+			}
+		})
+	}
+
+	if got, ok := cache.NextOccurrence("Okänd", time.Now()); ok {
+		t.Errorf("want no occurrence of unknown name, got %s", got)
+	}
+}
@@ -0,0 +1,91 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// RecordingTransport is a [http.RoundTripper] that saves the raw body of every
+// successful response into a directory. The saved files can be used as
+// fixtures, to validate changes to the parsing against real responses, such
+// as via [ParseHTML] or the namnsdagtest package.
+type RecordingTransport struct {
+	// Dir is the directory to save the responses to. It is created if it does
+	// not exist.
+	Dir string
+	// Next is the transport used to send the requests. Defaults to
+	// [http.DefaultTransport].
+	Next http.RoundTripper
+	// Clock tells when each response was recorded, which is used in the
+	// file names. Defaults to [SystemClock].
+	Clock Clock
+}
+
+var _ http.RoundTripper = RecordingTransport{}
+
+// RoundTrip implements [http.RoundTripper].
+func (t RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err := t.save(req, resp, body); err != nil {
+		return nil, fmt.Errorf("record fixture: %w", err)
+	}
+	return resp, nil
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func (t RecordingTransport) save(req *http.Request, resp *http.Response, body []byte) error {
+	if err := os.MkdirAll(t.Dir, 0755); err != nil {
+		return err
+	}
+	ext := ".html"
+	if strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		ext = ".json"
+	}
+	urlPath := strings.TrimSuffix(req.URL.Path, path.Ext(req.URL.Path))
+	name := fmt.Sprintf("%s_%s%s",
+		clockOrDefault(t.Clock).Now().UTC().Format("20060102T150405Z"),
+		strings.Trim(unsafeFileNameChars.ReplaceAllString(req.URL.Host+urlPath, "_"), "_"),
+		ext)
+	return os.WriteFile(filepath.Join(t.Dir, name), body, 0644)
+}
@@ -0,0 +1,144 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag/namnsdagtest"
+)
+
+// fixtureNames are some of the names found in all fixtures in
+// testdata/fixtures.
+var fixtureNames = []namnsdag.Name{
+	namnsdagtest.Name("Henrik", time.October, 17),
+	namnsdagtest.NewName("Henrika", time.October, 17),
+	namnsdagtest.UnofficialName("Harry", time.October, 17),
+	namnsdagtest.Name("Skottdagen", time.February, 29),
+}
+
+func TestParseFixtures(t *testing.T) {
+	fixtures, err := namnsdagtest.LoadFixtures("testdata/fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no fixtures found")
+	}
+	for _, f := range fixtures {
+		t.Run(f.Name, func(t *testing.T) {
+			var names []namnsdag.Name
+			if f.IsJSON() {
+				var dataset namnsdag.Dataset
+				if err := json.Unmarshal(f.Body, &dataset); err != nil {
+					t.Fatalf("decode dataset: %s", err)
+				}
+				names = dataset.Names
+			} else {
+				names, err = namnsdag.ParseHTMLStrict(bytes.NewReader(f.Body))
+				if err != nil {
+					t.Fatalf("parse HTML: %s", err)
+				}
+			}
+			assertHasNames(t, names, fixtureNames)
+		})
+	}
+}
+
+func TestReplayFixtures(t *testing.T) {
+	fixtures, err := namnsdagtest.LoadFixtures("testdata/fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range fixtures {
+		t.Run(f.Name, func(t *testing.T) {
+			srv := namnsdagtest.NewReplayServer(f)
+			defer srv.Close()
+			var source namnsdag.Source = namnsdag.WebSource{URL: srv.URL}
+			if f.IsJSON() {
+				source = namnsdag.DatasetSource{URL: srv.URL}
+			}
+			resp, err := source.Fetch(namnsdag.Request{})
+			if err != nil {
+				t.Fatalf("fetch: %s", err)
+			}
+			assertHasNames(t, resp.Names, fixtureNames)
+		})
+	}
+}
+
+func TestRecordingTransport(t *testing.T) {
+	srv := namnsdagtest.NewServer(fixtureNames...)
+	defer srv.Close()
+	dir := t.TempDir()
+	recordedAt := time.Date(2026, time.October, 17, 6, 0, 0, 0, time.UTC)
+	client := &http.Client{Transport: namnsdag.RecordingTransport{
+		Dir:   dir,
+		Clock: namnsdag.FixedClock(recordedAt),
+	}}
+
+	resp, err := srv.WebSource().Fetch(namnsdag.Request{HTTPClient: client})
+	if err != nil {
+		t.Fatalf("fetch: %s", err)
+	}
+	assertHasNames(t, resp.Names, fixtureNames)
+
+	fixtures, err := namnsdagtest.LoadFixtures(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) != 1 {
+		t.Fatalf("want 1 recorded fixture, got %d", len(fixtures))
+	}
+	if fixtures[0].IsJSON() {
+		t.Errorf("want HTML fixture, got %s", fixtures[0].Name)
+	}
+	if want := "20261017T060000Z_"; !strings.HasPrefix(fixtures[0].Name, want) {
+		t.Errorf("want fixture name prefixed with %s, got %s", want, fixtures[0].Name)
+	}
+	replayed, err := namnsdag.ParseHTML(bytes.NewReader(fixtures[0].Body))
+	if err != nil {
+		t.Fatalf("parse recorded fixture: %s", err)
+	}
+	assertHasNames(t, replayed, fixtureNames)
+}
+
+func assertHasNames(t *testing.T, got, want []namnsdag.Name) {
+	t.Helper()
+	for _, w := range want {
+		found := false
+		for _, g := range got {
+			if g.Name == w.Name && g.DoM() == w.DoM() && g.TypeOfName == w.TypeOfName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("want %s (%s) on %s, not found among %d names", w.Name, w.TypeOfName, w.DoM(), len(got))
+		}
+	}
+}
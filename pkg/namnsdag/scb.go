@@ -0,0 +1,262 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SCBNameStatsURL is the URL of the PxWeb API table from Statistics Sweden
+// (SCB) containing how many people in Sweden carry each first name. The table
+// must have one time variable and one variable of names.
+var SCBNameStatsURL = "https://api.scb.se/OV0104/v1/doris/sv/ssd/START/BE/BE0001/BE0001G/BE0001FNamn10"
+
+// ErrNameStatsNotFound is returned from [NameStatsTable.Lookup] when the
+// name is not in the statistics, which SCB omits for names carried by very
+// few people.
+var ErrNameStatsNotFound = errors.New("name not found in statistics")
+
+// NameStats is the statistics of a single name.
+type NameStats struct {
+	Name string `json:"name"`
+	// Count is the number of people in Sweden carrying the name as their
+	// first name.
+	Count int `json:"count"`
+	// Rank is the name's popularity, where 1 is the most common name.
+	Rank int `json:"rank"`
+	// Year the statistics are from.
+	Year int `json:"year"`
+}
+
+// NameStatsTable is the statistics of all names, fetched from Statistics
+// Sweden (SCB) using [FetchNameStatsTable]. The table is meant to be cached,
+// see [NameStatsCacheFile], as it changes once per year.
+type NameStatsTable struct {
+	Year      int       `json:"year"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	// Names is sorted by popularity, most common first.
+	Names []NameCount `json:"names"`
+}
+
+// NameCount is the number of people carrying a name.
+type NameCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Lookup returns the statistics of a name, compared using [NormalizeName].
+// Returns [ErrNameStatsNotFound] if the name is not in the table.
+func (t NameStatsTable) Lookup(name string) (NameStats, error) {
+	normalized := NormalizeName(name)
+	for i, n := range t.Names {
+		if NormalizeName(n.Name) == normalized {
+			return NameStats{
+				Name:  n.Name,
+				Count: n.Count,
+				Rank:  i + 1,
+				Year:  t.Year,
+			}, nil
+		}
+	}
+	return NameStats{}, fmt.Errorf("%w: %q", ErrNameStatsNotFound, name)
+}
+
+// pxwebMetadata is the response of a GET request to a PxWeb table.
+type pxwebMetadata struct {
+	Variables []struct {
+		Code       string   `json:"code"`
+		Values     []string `json:"values"`
+		ValueTexts []string `json:"valueTexts"`
+		Time       bool     `json:"time"`
+	} `json:"variables"`
+}
+
+type pxwebQuery struct {
+	Query    []pxwebQueryVariable `json:"query"`
+	Response struct {
+		Format string `json:"format"`
+	} `json:"response"`
+}
+
+type pxwebQueryVariable struct {
+	Code      string `json:"code"`
+	Selection struct {
+		Filter string   `json:"filter"`
+		Values []string `json:"values"`
+	} `json:"selection"`
+}
+
+type pxwebData struct {
+	Data []struct {
+		Key    []string `json:"key"`
+		Values []string `json:"values"`
+	} `json:"data"`
+}
+
+// FetchNameStatsTable fetches the statistics of all names for the latest
+// year from [SCBNameStatsURL]. The [Request.ETag] is ignored.
+func FetchNameStatsTable(req Request) (NameStatsTable, error) {
+	req.ETag = ""
+	resp, err := doGet(SCBNameStatsURL, req)
+	if err != nil {
+		return NameStatsTable{}, fmt.Errorf("fetch table metadata: %w", err)
+	}
+	var meta pxwebMetadata
+	err = json.NewDecoder(resp.Body).Decode(&meta)
+	resp.Body.Close()
+	if err != nil {
+		return NameStatsTable{}, fmt.Errorf("parse table metadata: %w", err)
+	}
+
+	var query pxwebQuery
+	query.Response.Format = "json"
+	nameTexts := map[string]string{}
+	nameIndex, timeIndex := -1, -1
+	for i, v := range meta.Variables {
+		q := pxwebQueryVariable{Code: v.Code}
+		if v.Time {
+			timeIndex = i
+			q.Selection.Filter = "top"
+			q.Selection.Values = []string{"1"}
+		} else {
+			nameIndex = i
+			q.Selection.Filter = "all"
+			q.Selection.Values = []string{"*"}
+			for j, code := range v.Values {
+				if j < len(v.ValueTexts) {
+					nameTexts[code] = v.ValueTexts[j]
+				}
+			}
+		}
+		query.Query = append(query.Query, q)
+	}
+	if nameIndex == -1 || timeIndex == -1 || len(meta.Variables) != 2 {
+		return NameStatsTable{}, errors.New("unexpected table: must have one time variable and one name variable")
+	}
+
+	body, err := json.Marshal(query)
+	if err != nil {
+		return NameStatsTable{}, err
+	}
+	resp, err = doPost(SCBNameStatsURL, body, req)
+	if err != nil {
+		return NameStatsTable{}, fmt.Errorf("fetch table data: %w", err)
+	}
+	var data pxwebData
+	err = json.NewDecoder(resp.Body).Decode(&data)
+	resp.Body.Close()
+	if err != nil {
+		return NameStatsTable{}, fmt.Errorf("parse table data: %w", err)
+	}
+
+	table := NameStatsTable{FetchedAt: req.clock().Now()}
+	// The same name may occur multiple times, such as once per gender.
+	counts := map[string]int{}
+	var names []string
+	for _, row := range data.Data {
+		if len(row.Key) != 2 || len(row.Values) == 0 {
+			continue
+		}
+		count, err := strconv.Atoi(row.Values[0])
+		if err != nil {
+			// Missing values are written as ".." by SCB.
+			continue
+		}
+		if year, err := strconv.Atoi(row.Key[timeIndex]); err == nil {
+			table.Year = year
+		}
+		name := nameTexts[row.Key[nameIndex]]
+		if name == "" {
+			name = row.Key[nameIndex]
+		}
+		if _, ok := counts[name]; !ok {
+			names = append(names, name)
+		}
+		counts[name] += count
+	}
+	for _, name := range names {
+		table.Names = append(table.Names, NameCount{Name: name, Count: counts[name]})
+	}
+	sort.SliceStable(table.Names, func(i, j int) bool {
+		return table.Names[i].Count > table.Names[j].Count
+	})
+	if len(table.Names) == 0 {
+		return NameStatsTable{}, errors.New("no names found in table")
+	}
+	return table, nil
+}
+
+func doPost(url string, body []byte, r Request) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return do(req, r)
+}
+
+// NameStatsCacheFile returns the path to the cache file of the name
+// statistics, which is separate from the cached names.
+func NameStatsCacheFile() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "name-stats.json"), nil
+}
+
+// LoadNameStatsTable reads the cached name statistics from a file.
+func LoadNameStatsTable(path string) (NameStatsTable, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return NameStatsTable{}, err
+	}
+	var table NameStatsTable
+	if err := json.Unmarshal(b, &table); err != nil {
+		return NameStatsTable{}, fmt.Errorf("%w: %w", ErrCacheCorrupt, err)
+	}
+	return table, nil
+}
+
+// SaveNameStatsTable writes the name statistics to a file. Nothing is written
+// if [ReadOnlyCache] is set.
+func SaveNameStatsTable(path string, table NameStatsTable) error {
+	if ReadOnlyCache {
+		return nil
+	}
+	b, err := json.Marshal(table)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0600)
+}
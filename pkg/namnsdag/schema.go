@@ -0,0 +1,152 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SchemaDriftError is reported when the fetched data does not match the
+// schema expected by this package, such as when the upstream website has been
+// redesigned. It contains a diagnostic of what was found instead.
+//
+// It is only returned as an error from [Source.Fetch] when using
+// [Request.Strict]. Otherwise it is reported via [Response.SchemaDrift].
+type SchemaDriftError struct {
+	// Problems lists the expectations that were not met, such as missing
+	// fields or that no names were found.
+	Problems []string
+	// Found describes the data that was found instead, if any.
+	Found string
+}
+
+// Error implements [error].
+func (e *SchemaDriftError) Error() string {
+	msg := "schema drift: " + strings.Join(e.Problems, "; ")
+	if e.Found != "" {
+		msg += "; found " + e.Found
+	}
+	return msg
+}
+
+var (
+	// requiredNameFields are the JSON fields of [Name] that must be set on
+	// all fetched names.
+	requiredNameFields = []string{"title", "day", "month", "type"}
+	// requiredDayNameFields are the JSON fields of [Name] that must be set
+	// on names fetched from a single day's page, where the date is implied.
+	requiredDayNameFields = []string{"title", "type"}
+)
+
+// checkSchema checks that the JSON contains a non-empty list of names at the
+// given path of object fields, where all names have the required fields.
+// Returns nil if no drift was detected.
+func checkSchema(data []byte, required []string, path ...string) *SchemaDriftError {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return &SchemaDriftError{Problems: []string{fmt.Sprintf("invalid JSON: %s", err)}}
+	}
+	for i, field := range path {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return &SchemaDriftError{
+				Problems: []string{fmt.Sprintf("%s is not an object", jsonPath(path[:i]))},
+				Found:    describeJSON(v),
+			}
+		}
+		if v, ok = obj[field]; !ok {
+			return &SchemaDriftError{
+				Problems: []string{fmt.Sprintf("missing field %s", jsonPath(path[:i+1]))},
+				Found:    fmt.Sprintf("%s as %s", jsonPath(path[:i]), describeJSON(obj)),
+			}
+		}
+	}
+	list, ok := v.([]any)
+	if !ok {
+		return &SchemaDriftError{
+			Problems: []string{fmt.Sprintf("%s is not a list", jsonPath(path))},
+			Found:    describeJSON(v),
+		}
+	}
+	if len(list) == 0 {
+		return &SchemaDriftError{
+			Problems: []string{fmt.Sprintf("no names found in %s", jsonPath(path))},
+		}
+	}
+
+	missing := map[string]int{}
+	for _, item := range list {
+		obj, _ := item.(map[string]any)
+		for _, field := range required {
+			if _, ok := obj[field]; !ok {
+				missing[field]++
+			}
+		}
+	}
+	var problems []string
+	for _, field := range required {
+		if missing[field] > 0 {
+			problems = append(problems, fmt.Sprintf("%d of %d names are missing field %q",
+				missing[field], len(list), field))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return &SchemaDriftError{
+		Problems: problems,
+		Found:    "names as " + describeJSON(list[0]),
+	}
+}
+
+func jsonPath(path []string) string {
+	if len(path) == 0 {
+		return "root"
+	}
+	return strings.Join(path, ".")
+}
+
+// describeJSON returns a short description of a decoded JSON value, such as
+// "object with fields [a b]" or "list of 3 items".
+func describeJSON(v any) string {
+	switch v := v.(type) {
+	case map[string]any:
+		fields := make([]string, 0, len(v))
+		for field := range v {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		return fmt.Sprintf("object with fields %v", fields)
+	case []any:
+		return fmt.Sprintf("list of %d items", len(v))
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
@@ -0,0 +1,109 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeName returns a normalized form of a name, meant for comparing
+// names with each other. Surrounding whitespace is trimmed, the name is
+// normalized to Unicode NFC, and case folded.
+//
+// For example "ÅSA", "åsa", and " Åsa " all normalize to the same value.
+func NormalizeName(name string) string {
+	return cases.Fold().String(norm.NFC.String(strings.TrimSpace(name)))
+}
+
+// Names returns all names from all days, sorted using [SortNames].
+func (c Cache) Names() []Name {
+	var names []Name
+	for _, dom := range c.SortedDays() {
+		names = append(names, c.NamesPerDay[dom]...)
+	}
+	SortNames(names)
+	return names
+}
+
+// FindName returns all occurrences of a given name, or of a name with the
+// given spelling variant, compared using [NormalizeName]. Most names only
+// occur once, but some occur multiple times in the year.
+func (c Cache) FindName(name string) []Name {
+	normalized := NormalizeName(name)
+	var found []Name
+	for _, n := range c.Names() {
+		for _, spelling := range n.Spellings() {
+			if NormalizeName(spelling) == normalized {
+				found = append(found, n)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// Search returns all names where the name, or any of its spelling variants,
+// contains the query, compared using [NormalizeName]. Names that start with
+// the query are sorted first, and then the matches are sorted using
+// [SortNamesByName].
+func (c Cache) Search(query string) []Name {
+	normalized := NormalizeName(query)
+	var prefixMatches, otherMatches []Name
+	for _, n := range c.Names() {
+		var isPrefix, isContained bool
+		for _, spelling := range n.Spellings() {
+			name := NormalizeName(spelling)
+			isPrefix = isPrefix || strings.HasPrefix(name, normalized)
+			isContained = isContained || strings.Contains(name, normalized)
+		}
+		switch {
+		case isPrefix:
+			prefixMatches = append(prefixMatches, n)
+		case isContained:
+			otherMatches = append(otherMatches, n)
+		}
+	}
+	SortNamesByName(prefixMatches)
+	SortNamesByName(otherMatches)
+	return append(prefixMatches, otherMatches...)
+}
+
+// SearchRegexp returns all names where the name, or any of its spelling
+// variants, matches the regular expression, sorted using [SortNamesByName].
+// The names are matched as-is, so use the "(?i)" flag for case insensitive
+// matching.
+func (c Cache) SearchRegexp(re *regexp.Regexp) []Name {
+	var matches []Name
+	for _, n := range c.Names() {
+		for _, spelling := range n.Spellings() {
+			if re.MatchString(spelling) {
+				matches = append(matches, n)
+				break
+			}
+		}
+	}
+	SortNamesByName(matches)
+	return matches
+}
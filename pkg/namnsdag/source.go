@@ -0,0 +1,202 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DatasetBaseURL is the base URL of the dataset snapshots, which are
+// published as release artifacts. See [DatasetURL].
+var DatasetBaseURL = "https://github.com/jilleJr/namnsdag/releases/download"
+
+// Source is a provider of names.
+type Source interface {
+	// Fetch retrieves all names from the source.
+	Fetch(req Request) (Response, error)
+	// String returns a human-readable description of the source, such as
+	// its URL.
+	String() string
+}
+
+// DaySource is a [Source] that can also fetch the names of a single day,
+// which is more lightweight than fetching all names.
+type DaySource interface {
+	Source
+	// FetchDay retrieves the names of a single day from the source.
+	FetchDay(dom DoM, req Request) (Response, error)
+}
+
+var (
+	_ DaySource = WebSource{}
+	_ Source    = DatasetSource{}
+)
+
+// WebSource fetches names by scraping the HTML of the
+// [https://dagensnamnsdag.nu] website.
+type WebSource struct {
+	// URL of the website's page containing all names. Defaults to [URL].
+	URL string
+}
+
+// Fetch implements [Source].
+func (s WebSource) Fetch(req Request) (Response, error) {
+	return fetchFromWeb(s.url(), req, requiredNameFields)
+}
+
+// FetchDay implements [DaySource], by scraping the website's page for a
+// single day, found at [WebSource.DayURL].
+func (s WebSource) FetchDay(dom DoM, req Request) (Response, error) {
+	if err := dom.Validate(); err != nil {
+		return Response{}, err
+	}
+	resp, err := fetchFromWeb(s.DayURL(dom), req, requiredDayNameFields)
+	if err != nil {
+		return resp, err
+	}
+	// The names on a day's page may lack the date, as it is implied.
+	for i, name := range resp.Names {
+		if name.Month == 0 && name.Day == 0 {
+			resp.Names[i].Month = dom.Month
+			resp.Names[i].Day = dom.Day
+		}
+	}
+	return resp, nil
+}
+
+// DayURL returns the URL of the website's page for a single day, such as
+// "https://dagensnamnsdag.nu/namnsdagar/oktober/17".
+func (s WebSource) DayURL(dom DoM) string {
+	return fmt.Sprintf("%s/%s/%d", s.url(), swedishMonthNames[dom.Month-1], dom.Day)
+}
+
+var swedishMonthNames = [...]string{
+	"januari", "februari", "mars", "april", "maj", "juni",
+	"juli", "augusti", "september", "oktober", "november", "december",
+}
+
+// String implements [Source].
+func (s WebSource) String() string {
+	return s.url()
+}
+
+func (s WebSource) url() string {
+	if s.URL == "" {
+		return URL
+	}
+	return s.URL
+}
+
+// Dataset is the model of a dataset snapshot: a JSON file containing all
+// names, as fetched at a given point in time.
+type Dataset struct {
+	// Version of the dataset, such as "v2024".
+	Version string `json:"version,omitempty"`
+	Names   []Name `json:"names"`
+}
+
+// DatasetURL returns the URL to a versioned dataset snapshot, such as
+// "v2024", published as a release artifact.
+func DatasetURL(version string) string {
+	return fmt.Sprintf("%s/dataset-%s/names.json", DatasetBaseURL, version)
+}
+
+// DatasetSource fetches names from a JSON file, in the format of [Dataset].
+// It can be used with the versioned dataset snapshots, see [DatasetURL], or
+// with any mirror of the names.
+type DatasetSource struct {
+	URL string
+
+	// PublicKey, if set, is used to verify the dataset against its detached
+	// minisign signature before it is parsed.
+	PublicKey *MinisignPublicKey
+	// SignatureURL is the URL of the detached minisign signature. Defaults to
+	// the dataset's URL with the ".minisig" suffix.
+	SignatureURL string
+}
+
+// Fetch implements [Source].
+func (s DatasetSource) Fetch(req Request) (Response, error) {
+	resp, err := doGet(s.URL, req)
+	if errors.Is(err, ErrHTTPNotModified) {
+		return Response{ETag: req.ETag}, err
+	}
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Response{}, err
+	}
+	if s.PublicKey != nil {
+		if err := s.verify(body, req); err != nil {
+			return Response{}, err
+		}
+	}
+	var dataset Dataset
+	if err := json.Unmarshal(body, &dataset); err != nil {
+		return Response{}, fmt.Errorf("parse dataset: %w", err)
+	}
+	if err := validateNames(dataset.Names); err != nil {
+		return Response{}, err
+	}
+	drift := checkSchema(body, requiredNameFields, "names")
+	if drift != nil && req.Strict {
+		return Response{}, drift
+	}
+	dataset.Names = SplitCombinedNames(dataset.Names)
+	SortNames(dataset.Names)
+	return Response{
+		Names:       dataset.Names,
+		ETag:        resp.Header.Get("etag"),
+		SchemaDrift: drift,
+	}, nil
+}
+
+// String implements [Source].
+func (s DatasetSource) String() string {
+	return s.URL
+}
+
+func (s DatasetSource) verify(body []byte, req Request) error {
+	sigURL := s.SignatureURL
+	if sigURL == "" {
+		sigURL = s.URL + ".minisig"
+	}
+	req.ETag = ""
+	resp, err := doGet(sigURL, req)
+	if err != nil {
+		return fmt.Errorf("fetch dataset signature: %w", err)
+	}
+	defer resp.Body.Close()
+	sig, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("fetch dataset signature: %w", err)
+	}
+	if err := s.PublicKey.Verify(body, sig); err != nil {
+		return fmt.Errorf("verify dataset: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag/namnsdagtest"
+)
+
+func TestWebSourceFetch(t *testing.T) {
+	srv := namnsdagtest.NewServer(fixtureNames...)
+	defer srv.Close()
+
+	resp, err := srv.WebSource().Fetch(namnsdag.Request{})
+	if err != nil {
+		t.Fatalf("fetch: %s", err)
+	}
+	if len(resp.Names) != len(fixtureNames) {
+		t.Errorf("want %d names, got %d", len(fixtureNames), len(resp.Names))
+	}
+	assertHasNames(t, resp.Names, fixtureNames)
+	if resp.ETag != srv.ETag() {
+		t.Errorf("want ETag %q, got %q", srv.ETag(), resp.ETag)
+	}
+}
+
+func TestWebSourceNotModified(t *testing.T) {
+	srv := namnsdagtest.NewServer(fixtureNames...)
+	defer srv.Close()
+	source := srv.WebSource()
+
+	resp, err := source.Fetch(namnsdag.Request{})
+	if err != nil {
+		t.Fatalf("first fetch: %s", err)
+	}
+	_, err = source.Fetch(namnsdag.Request{ETag: resp.ETag})
+	if !errors.Is(err, namnsdag.ErrHTTPNotModified) {
+		t.Fatalf("want %v with unchanged ETag, got %v", namnsdag.ErrHTTPNotModified, err)
+	}
+
+	lukas := namnsdagtest.Name("Lukas", time.October, 18)
+	srv.SetNames(lukas)
+	resp, err = source.Fetch(namnsdag.Request{ETag: resp.ETag})
+	if err != nil {
+		t.Fatalf("fetch after change: %s", err)
+	}
+	if len(resp.Names) != 1 || resp.Names[0].Name != lukas.Name {
+		t.Errorf("want only %s after change, got %v", lukas.Name, resp.Names)
+	}
+	if got := srv.Hits(); got != 3 {
+		t.Errorf("want 3 hits, got %d", got)
+	}
+}
+
+func TestWebSourceFallsBackToDataRoute(t *testing.T) {
+	const buildID = "abc123"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/namnsdagar", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html>
+<body>
+<script id="__NEXT_DATA__" type="application/json">{"props":{"pageProps":{}}}</script>
+<script src="/_next/static/%s/_buildManifest.js"></script>
+</body>
+</html>
+`, buildID)
+	})
+	mux.HandleFunc("/_next/data/"+buildID+"/namnsdagar.json", func(w http.ResponseWriter, r *http.Request) {
+		var data struct {
+			PageProps struct {
+				Names []namnsdag.Name `json:"names"`
+			} `json:"pageProps"`
+		}
+		data.PageProps.Names = fixtureNames
+		json.NewEncoder(w).Encode(data)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := namnsdag.WebSource{URL: srv.URL + "/namnsdagar"}.Fetch(namnsdag.Request{})
+	if err != nil {
+		t.Fatalf("fetch: %s", err)
+	}
+	assertHasNames(t, resp.Names, fixtureNames)
+	if resp.SchemaDrift != nil {
+		t.Errorf("want no schema drift, got %s", resp.SchemaDrift)
+	}
+}
+
+func TestDefaultUserAgentHasVersion(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.UserAgent()
+		w.Write(namnsdagtest.NextJSHTML(fixtureNames))
+	}))
+	defer srv.Close()
+
+	if _, err := (namnsdag.WebSource{URL: srv.URL}).Fetch(namnsdag.Request{}); err != nil {
+		t.Fatalf("fetch: %s", err)
+	}
+	if want := "namnsdag-go/" + namnsdag.Version + " "; namnsdag.Version == "" || !strings.HasPrefix(got, want) {
+		t.Errorf("want User-Agent starting with %q, got %q", want, got)
+	}
+}
+
+func TestDatasetSourceFetch(t *testing.T) {
+	srv := namnsdagtest.NewServer(fixtureNames...)
+	defer srv.Close()
+
+	resp, err := srv.DatasetSource().Fetch(namnsdag.Request{})
+	if err != nil {
+		t.Fatalf("fetch: %s", err)
+	}
+	assertHasNames(t, resp.Names, fixtureNames)
+}
+
+func TestDatasetSourceKeepsThemeDayTitles(t *testing.T) {
+	themeDay := namnsdag.Name{
+		Slug:       "mat-och-dryckesdagen",
+		Name:       "Mat- och dryckesdagen",
+		Month:      time.October,
+		Day:        17,
+		TypeOfName: namnsdag.TypeThemeDay,
+	}
+	srv := namnsdagtest.NewServer(themeDay, namnsdagtest.Name("Alf och Alvar", time.October, 18))
+	defer srv.Close()
+
+	resp, err := srv.DatasetSource().Fetch(namnsdag.Request{})
+	if err != nil {
+		t.Fatalf("fetch: %s", err)
+	}
+	assertHasNames(t, resp.Names, []namnsdag.Name{
+		themeDay,
+		namnsdagtest.Name("Alf", time.October, 18),
+		namnsdagtest.Name("Alvar", time.October, 18),
+	})
+	if len(resp.Names) != 3 {
+		t.Errorf("want 3 names, got %v", resp.Names)
+	}
+}
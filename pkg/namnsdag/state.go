@@ -0,0 +1,76 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// StateDir overrides the directory of the state files, such as for a mounted
+// volume in a container. See [StateFile] for the default.
+var StateDir = ""
+
+// StateFile returns the path to a state file, such as when the names were
+// last fetched or shown. Unlike the cache, which may be wiped freely, the
+// state is meant to be kept, and is therefore stored separately: in
+// $XDG_STATE_HOME/namnsdag, defaulting to ~/.local/state/namnsdag. On
+// Windows and macOS, where there is no such convention, it is stored in
+// a "state" directory inside the local app data or application support
+// directory respectively.
+func StateFile(name string) (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+func stateDir() (string, error) {
+	if StateDir != "" {
+		return StateDir, nil
+	}
+	if dir := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "namnsdag"), nil
+	}
+	switch runtime.GOOS {
+	case "windows":
+		// %LocalAppData%, which unlike the cache dir on other OS's is not
+		// meant to be wiped
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "namnsdag", "state"), nil
+	case "darwin", "ios":
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "namnsdag", "state"), nil
+	}
+	dir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ".local", "state", "namnsdag"), nil
+}
@@ -0,0 +1,181 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import (
+	"sync"
+	"time"
+)
+
+// Store is an in-memory holder of a parsed [Cache], safe for concurrent use.
+// It is meant for long-running processes, such as servers and bots, that
+// perform many lookups without wanting to re-read the cache file each time.
+//
+// The zero value is an empty store, ready to use.
+type Store struct {
+	// Clock tells what day it is in [Store.Today]. Defaults to
+	// [SystemClock].
+	Clock Clock
+
+	mu    sync.RWMutex
+	cache Cache
+}
+
+// NewStore creates a new [Store] populated with the given cache.
+func NewStore(cache Cache) *Store {
+	return &Store{cache: cache}
+}
+
+// LoadStore creates a new [Store] populated from the cache file.
+// See [LoadCache] for more details.
+func LoadStore() (*Store, error) {
+	var s Store
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Reload re-reads the cache file and replaces the store's content. The
+// content is only replaced if the load succeeds, so concurrent readers will
+// either see the old or the new content in its entirety.
+func (s *Store) Reload() error {
+	cache, err := LoadCache()
+	if err != nil {
+		return err
+	}
+	s.Set(cache)
+	return nil
+}
+
+// Set replaces the store's content with a copy of the cache, so the cache
+// can be modified afterwards without affecting the store.
+func (s *Store) Set(cache Cache) {
+	cache = cloneCache(cache)
+	s.mu.Lock()
+	s.cache = cache
+	s.mu.Unlock()
+}
+
+// Cache returns a copy of the store's content. The maps and names are
+// copied so they can be modified without affecting the store.
+func (s *Store) Cache() Cache {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return cloneCache(s.cache)
+}
+
+// cloneCache returns a deep copy of the cache.
+func cloneCache(c Cache) Cache {
+	clone := c
+	if c.NamesPerDay != nil {
+		clone.NamesPerDay = make(map[DoM][]Name, len(c.NamesPerDay))
+		for dom, names := range c.NamesPerDay {
+			clone.NamesPerDay[dom] = cloneNames(names)
+		}
+	}
+	if c.Days != nil {
+		clone.Days = make(map[DoM]DayInfo, len(c.Days))
+		for dom, info := range c.Days {
+			clone.Days[dom] = info
+		}
+	}
+	if c.LastAttemptAt != nil {
+		lastAttemptAt := *c.LastAttemptAt
+		clone.LastAttemptAt = &lastAttemptAt
+	}
+	return clone
+}
+
+// cloneNames returns a copy of the names, including their variants.
+func cloneNames(names []Name) []Name {
+	clone := append([]Name(nil), names...)
+	for i := range clone {
+		clone[i].Variants = append([]string(nil), clone[i].Variants...)
+	}
+	return clone
+}
+
+// Names returns a copy of the names celebrated on a given day.
+func (s *Store) Names(dom DoM) []Name {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Name(nil), s.cache.NamesPerDay[dom]...)
+}
+
+// Days returns the days that have names, in calendar order.
+func (s *Store) Days() []DoM {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cache.SortedDays()
+}
+
+// DayInfo returns when, and from where, the names of a day were last
+// fetched. See [Cache.DayInfo] for more details.
+func (s *Store) DayInfo(dom DoM) DayInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cache.DayInfo(dom)
+}
+
+// Upcoming returns a copy of the names of n consecutive days, starting with
+// the day of from. See [Upcoming] for more details.
+func (s *Store) Upcoming(from time.Time, n int) []DayNames {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Upcoming(s.cache, from, n)
+}
+
+// Dataset returns a copy of all names in the store, sorted using
+// [SortNames].
+func (s *Store) Dataset() Dataset {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Dataset{Names: s.cache.Names()}
+}
+
+// Today returns a copy of the names celebrated today in the given time zone,
+// according to the store's clock. A nil location means the local time zone.
+func (s *Store) Today(loc *time.Location) []Name {
+	return s.Names(TodayAt(s.Clock, loc))
+}
+
+// NextOccurrence returns the date of the next namnsdag of a name.
+// See [Cache.NextOccurrence] for more details.
+func (s *Store) NextOccurrence(name string, after time.Time) (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cache.NextOccurrence(name, after)
+}
+
+// UpdatedAt returns when the store's content was last fetched.
+func (s *Store) UpdatedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cache.UpdatedAt
+}
+
+// ETag returns the HTTP ETag of the store's content.
+func (s *Store) ETag() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cache.ETag
+}
@@ -0,0 +1,166 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag/namnsdagtest"
+)
+
+func TestStoreToday(t *testing.T) {
+	updatedAt := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	store := namnsdag.NewStore(namnsdagtest.Cache(updatedAt, fixtureNames...))
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		t.Skipf("load time zone: %s", err)
+	}
+
+	// 22:30 UTC on the 16th is already the 17th in Stockholm
+	store.Clock = namnsdag.FixedClock(time.Date(2026, time.October, 16, 22, 30, 0, 0, time.UTC))
+	if got := store.Today(time.UTC); len(got) != 0 {
+		t.Errorf("want no names on the 16th in UTC, got %v", got)
+	}
+	got := store.Today(stockholm)
+	assertHasNames(t, got, fixtureNames[:3])
+	if len(got) != 3 {
+		t.Errorf("want 3 names on the 17th in Stockholm, got %v", got)
+	}
+	if !store.UpdatedAt().Equal(updatedAt) {
+		t.Errorf("want updated at %s, got %s", updatedAt, store.UpdatedAt())
+	}
+}
+
+func TestStoreFromSource(t *testing.T) {
+	source := &namnsdagtest.Source{Names: fixtureNames, ETag: `"v1"`}
+	resp, err := source.Fetch(namnsdag.Request{})
+	if err != nil {
+		t.Fatalf("fetch: %s", err)
+	}
+	var store namnsdag.Store
+	cache := namnsdagtest.Cache(time.Now(), resp.Names...)
+	cache.ETag = resp.ETag
+	store.Set(cache)
+
+	if _, err := source.Fetch(namnsdag.Request{ETag: store.ETag()}); !errors.Is(err, namnsdag.ErrHTTPNotModified) {
+		t.Errorf("want %v when fetching with the stored ETag, got %v", namnsdag.ErrHTTPNotModified, err)
+	}
+	if got := len(source.Requests()); got != 2 {
+		t.Errorf("want 2 requests, got %d", got)
+	}
+	after := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	next, ok := store.NextOccurrence("Skottdagen", after)
+	if !ok {
+		t.Fatal("want next occurrence of Skottdagen")
+	}
+	if want := time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("want next Skottdagen on %s, got %s", want, next)
+	}
+}
+
+func TestStoreCacheIsCopy(t *testing.T) {
+	dom := fixtureNames[0].DoM()
+	cache := namnsdagtest.Cache(time.Now(), fixtureNames...)
+	cache.Days = map[namnsdag.DoM]namnsdag.DayInfo{dom: {Source: "original"}}
+	cache.Partial = true
+	store := namnsdag.NewStore(cache)
+
+	cache = store.Cache()
+	cache.NamesPerDay[dom][0].Name = "Changed"
+	delete(cache.NamesPerDay, dom)
+	cache.Days[dom] = namnsdag.DayInfo{Source: "changed"}
+
+	names := store.Names(dom)
+	if len(names) == 0 || names[0].Name == "Changed" {
+		t.Errorf("want store unaffected by changes to its copy, got %v", names)
+	}
+	if got := store.DayInfo(dom); got.Source != "original" {
+		t.Errorf("want store's day info unaffected by changes to its copy, got %v", got)
+	}
+}
+
+func TestStoreSetCopiesCache(t *testing.T) {
+	dom := fixtureNames[0].DoM()
+	cache := namnsdagtest.Cache(time.Now(), fixtureNames...)
+	cache.Days = map[namnsdag.DoM]namnsdag.DayInfo{dom: {Source: "original"}}
+	cache.Partial = true
+	store := namnsdag.NewStore(namnsdag.Cache{})
+	store.Set(cache)
+
+	cache.NamesPerDay[dom][0].Name = "Changed"
+	cache.Days[dom] = namnsdag.DayInfo{Source: "changed"}
+
+	names := store.Names(dom)
+	if len(names) == 0 || names[0].Name == "Changed" {
+		t.Errorf("want store unaffected by changes to the set cache, got %v", names)
+	}
+	if got := store.DayInfo(dom); got.Source != "original" {
+		t.Errorf("want store's day info unaffected by changes to the set cache, got %v", got)
+	}
+}
+
+func TestStoreUpcoming(t *testing.T) {
+	store := namnsdag.NewStore(namnsdagtest.Cache(time.Now(), fixtureNames...))
+	from := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+
+	upcoming := store.Upcoming(from, 2)
+	if len(upcoming) != 2 {
+		t.Fatalf("want 2 days, got %v", upcoming)
+	}
+	assertHasNames(t, upcoming[1].Names, fixtureNames[:3])
+	upcoming[1].Names[0].Name = "Changed"
+
+	dataset := store.Dataset()
+	if len(dataset.Names) != len(fixtureNames) {
+		t.Errorf("want %d names in dataset, got %v", len(fixtureNames), dataset.Names)
+	}
+	for _, name := range dataset.Names {
+		if name.Name == "Changed" {
+			t.Errorf("want store unaffected by changes to upcoming names, got %v", dataset.Names)
+		}
+	}
+}
+
+func TestStoreConcurrentUse(t *testing.T) {
+	var store namnsdag.Store
+	dom := fixtureNames[0].DoM()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			store.Set(namnsdagtest.Cache(time.Now(), fixtureNames...))
+		}()
+		go func() {
+			defer wg.Done()
+			store.Names(dom)
+			store.Days()
+		}()
+	}
+	wg.Wait()
+	if got := store.Names(dom); len(got) != 3 {
+		t.Errorf("want 3 names on %s, got %v", dom, got)
+	}
+}
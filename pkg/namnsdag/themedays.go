@@ -0,0 +1,51 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:embed themedays.json
+var themeDaysJSON []byte
+
+var themeDays = func() []Name {
+	var days []Name
+	if err := json.Unmarshal(themeDaysJSON, &days); err != nil {
+		panic(fmt.Sprintf("parse embedded theme days: %s", err))
+	}
+	for i := range days {
+		days[i].Slug = slugify(days[i].Name)
+		days[i].TypeOfName = TypeThemeDay
+	}
+	return days
+}()
+
+// ThemeDays returns the built-in list of well-known Swedish theme days
+// ("temadagar") that are on the same date every year, such as
+// "Kanelbullens dag", as names of type [TypeThemeDay], sorted by date.
+func ThemeDays() []Name {
+	days := append([]Name(nil), themeDays...)
+	SortNames(days)
+	return days
+}
@@ -0,0 +1,57 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jilleJr/namnsdag/v3/pkg/namnsdag"
+)
+
+func TestThemeDays(t *testing.T) {
+	days := namnsdag.ThemeDays()
+	if len(days) == 0 {
+		t.Fatal("want built-in theme days")
+	}
+	for i, day := range days {
+		if day.TypeOfName != namnsdag.TypeThemeDay {
+			t.Errorf("%s: want type %s, got %s", day.Name, namnsdag.TypeThemeDay, day.TypeOfName)
+		}
+		if err := day.DoM().Validate(); err != nil {
+			t.Errorf("%s: %s", day.Name, err)
+		}
+		if day.Slug == "" {
+			t.Errorf("%s: want slug", day.Name)
+		}
+		if i > 0 && day.DoM().Before(days[i-1].DoM()) {
+			t.Errorf("want sorted by date, got %s before %s", days[i-1].Name, day.Name)
+		}
+	}
+	want := namnsdag.DoM{Month: time.October, Day: 4}
+	found := false
+	for _, day := range days {
+		found = found || (day.Name == "Kanelbullens dag" && day.DoM() == want)
+	}
+	if !found {
+		t.Errorf("want Kanelbullens dag on %s", want)
+	}
+}
@@ -0,0 +1,291 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DatasetSchema is the JSON Schema of the [Dataset] format, as used by
+// [DatasetSource]. See [ValidateDataset].
+//
+//go:embed schemas/dataset.schema.json
+var DatasetSchema []byte
+
+// CacheSchema is the JSON Schema of the cache file format, as written by
+// [WriteCache]. See [ValidateCacheJSON].
+//
+//go:embed schemas/cache.schema.json
+var CacheSchema []byte
+
+// ValidationError lists all the ways a JSON document does not conform to a
+// schema, such as [DatasetSchema].
+type ValidationError struct {
+	// Problems are the violations, each prefixed with the path to the
+	// invalid value, such as "names[3].month: must be between 1 and 12".
+	Problems []string
+}
+
+// Error implements [error].
+func (e *ValidationError) Error() string {
+	return "invalid: " + strings.Join(e.Problems, "; ")
+}
+
+// ValidateDataset checks that the JSON conforms to [DatasetSchema], and that
+// the dates of all names exist. Returns a [*ValidationError] listing all
+// problems, or nil if the dataset is valid.
+func ValidateDataset(data []byte) error {
+	var v validator
+	if obj, ok := v.object("root", data); ok {
+		v.optionalString("version", obj["version"])
+		if names, ok := obj["names"]; !ok {
+			v.addf("names", "missing required field")
+		} else if list, ok := names.([]any); !ok {
+			v.addf("names", "must be a list, found %s", describeJSON(names))
+		} else if len(list) == 0 {
+			v.addf("names", "must contain at least one name")
+		} else {
+			for i, name := range list {
+				v.name(fmt.Sprintf("names[%d]", i), name, nil)
+			}
+		}
+	}
+	return v.err()
+}
+
+// ValidateCacheJSON checks that the uncompressed JSON conforms to
+// [CacheSchema], and that the dates of all names exist. Returns a
+// [*ValidationError] listing all problems, or nil if the cache is valid.
+func ValidateCacheJSON(data []byte) error {
+	var v validator
+	if obj, ok := v.object("root", data); ok {
+		if version, ok := obj["schemaVersion"]; ok {
+			if n, ok := v.integer("schemaVersion", version); ok && n < 1 {
+				v.addf("schemaVersion", "must be at least 1")
+			}
+		}
+		v.optionalString("etag", obj["etag"])
+		v.optionalTime("updatedAt", obj["updatedAt"])
+		v.optionalString("source", obj["source"])
+		v.optionalTime("lastAttemptAt", obj["lastAttemptAt"])
+		if sum, ok := obj["checksum"]; ok {
+			if s, ok := v.string("checksum", sum); ok && !checksumPattern.MatchString(s) {
+				v.addf("checksum", `must be on the format "sha256:<hex>"`)
+			}
+		}
+		if partial, ok := obj["partial"]; ok {
+			if _, ok := partial.(bool); !ok {
+				v.addf("partial", "must be a boolean, found %s", describeJSON(partial))
+			}
+		}
+		if perDay, ok := obj["namesPerDay"]; !ok {
+			v.addf("namesPerDay", "missing required field")
+		} else {
+			v.days("namesPerDay", perDay, func(path string, dom DoM, value any) {
+				list, ok := value.([]any)
+				if !ok {
+					v.addf(path, "must be a list, found %s", describeJSON(value))
+					return
+				}
+				for i, name := range list {
+					v.name(fmt.Sprintf("%s[%d]", path, i), name, &dom)
+				}
+			})
+		}
+		if days, ok := obj["days"]; ok {
+			v.days("days", days, func(path string, _ DoM, value any) {
+				info, ok := value.(map[string]any)
+				if !ok {
+					v.addf(path, "must be an object, found %s", describeJSON(value))
+					return
+				}
+				v.optionalTime(path+".updatedAt", info["updatedAt"])
+				v.optionalString(path+".source", info["source"])
+			})
+		}
+	}
+	return v.err()
+}
+
+var checksumPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// validator collects the problems found when validating a JSON document.
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(path, format string, args ...any) {
+	v.problems = append(v.problems, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: v.problems}
+}
+
+func (v *validator) object(path string, data []byte) (map[string]any, bool) {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		v.addf(path, "invalid JSON: %s", err)
+		return nil, false
+	}
+	obj, ok := value.(map[string]any)
+	if !ok {
+		v.addf(path, "must be an object, found %s", describeJSON(value))
+	}
+	return obj, ok
+}
+
+func (v *validator) string(path string, value any) (string, bool) {
+	s, ok := value.(string)
+	if !ok {
+		v.addf(path, "must be a string, found %s", describeJSON(value))
+	}
+	return s, ok
+}
+
+func (v *validator) optionalString(path string, value any) {
+	if value != nil {
+		v.string(path, value)
+	}
+}
+
+func (v *validator) optionalTime(path string, value any) {
+	if value == nil {
+		return
+	}
+	if s, ok := v.string(path, value); ok {
+		if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+			v.addf(path, "must be a RFC 3339 date-time, found %q", s)
+		}
+	}
+}
+
+func (v *validator) integer(path string, value any) (int, bool) {
+	f, ok := value.(float64)
+	if !ok || f != math.Trunc(f) {
+		v.addf(path, "must be an integer, found %s", describeJSON(value))
+		return 0, false
+	}
+	return int(f), true
+}
+
+func (v *validator) intBetween(path string, value any, min, max int) (int, bool) {
+	n, ok := v.integer(path, value)
+	if ok && (n < min || n > max) {
+		v.addf(path, "must be between %d and %d, found %d", min, max, n)
+		return n, false
+	}
+	return n, ok
+}
+
+// days validates an object keyed by days on the format MM-DD, in calendar
+// order, calling fn for each valid day.
+func (v *validator) days(path string, value any, fn func(path string, dom DoM, value any)) {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		v.addf(path, "must be an object, found %s", describeJSON(value))
+		return
+	}
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var dom DoM
+		if !dayKeyPattern.MatchString(key) || dom.UnmarshalText([]byte(key)) != nil {
+			v.addf(path, "invalid day %q, must be on the format MM-DD", key)
+			continue
+		}
+		if err := dom.Validate(); err != nil {
+			v.addf(path, "invalid day %q: %s", key, err)
+			continue
+		}
+		fn(fmt.Sprintf("%s[%q]", path, key), dom, obj[key])
+	}
+}
+
+var dayKeyPattern = regexp.MustCompile(`^[0-9]{2}-[0-9]{2}$`)
+
+// name validates a name object. If dom is set, the name must be on that day.
+func (v *validator) name(path string, value any, dom *DoM) {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		v.addf(path, "must be an object, found %s", describeJSON(value))
+		return
+	}
+	for _, field := range requiredNameFields {
+		if _, ok := obj[field]; !ok {
+			v.addf(path, "missing required field %q", field)
+		}
+	}
+	v.optionalString(path+".slug", obj["slug"])
+	v.optionalString(path+".gender", obj["gender"])
+	if variants, ok := obj["variants"]; ok {
+		if list, ok := variants.([]any); !ok {
+			v.addf(path+".variants", "must be a list, found %s", describeJSON(variants))
+		} else {
+			for i, variant := range list {
+				variantPath := fmt.Sprintf("%s.variants[%d]", path, i)
+				if s, ok := v.string(variantPath, variant); ok && s == "" {
+					v.addf(variantPath, "must not be empty")
+				}
+			}
+		}
+	}
+	if title, ok := obj["title"]; ok {
+		if s, ok := v.string(path+".title", title); ok && s == "" {
+			v.addf(path+".title", "must not be empty")
+		}
+	}
+	if typ, ok := obj["type"]; ok {
+		if s, ok := v.string(path+".type", typ); ok && s == "" {
+			v.addf(path+".type", "must not be empty")
+		}
+	}
+	var day, month int
+	var validDay, validMonth bool
+	if value, ok := obj["day"]; ok {
+		day, validDay = v.intBetween(path+".day", value, 1, 31)
+	}
+	if value, ok := obj["month"]; ok {
+		month, validMonth = v.intBetween(path+".month", value, 1, 12)
+	}
+	if !validDay || !validMonth {
+		return
+	}
+	nameDoM := NewDoM(time.Month(month), day)
+	if err := nameDoM.Validate(); err != nil {
+		v.addf(path, "invalid date %s: %s", nameDoM, err)
+	} else if dom != nil && nameDoM != *dom {
+		v.addf(path, "is on %s, but listed under %s", nameDoM, *dom)
+	}
+}
@@ -0,0 +1,125 @@
+// Simple CLI for fetching the list of names to celebrate today.
+// <https://github.com/jilleJr/namnsdag>
+//
+// SPDX-FileCopyrightText: 2022 Kalle Fagerberg
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify it
+// under the terms of the GNU General Public License as published by the
+// Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or
+// FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public License for
+// more details.
+//
+// You should have received a copy of the GNU General Public License along
+// with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package namnsdag
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// combinedNameSeparators separate different names listed in a single entry,
+// such as "Alf och Alvar".
+var combinedNameSeparators = []string{" och ", " & ", ", "}
+
+// variantSeparator separates spelling variants of the same name, such as
+// "Mikael/Michael".
+const variantSeparator = "/"
+
+// Spellings returns the name followed by its spelling variants, if any.
+func (n Name) Spellings() []string {
+	return append([]string{n.Name}, n.Variants...)
+}
+
+// SplitCombinedNames splits the entries that list multiple names, such as
+// "Alf och Alvar", into separate names, and the entries that list spelling
+// variants, such as "Mikael/Michael", into a name with [Name.Variants]. This
+// is done when fetching, so that each name can be found on its own, such as
+// via [Cache.FindName]. Names that don't need to be split are kept as-is, as
+// are theme days, whose titles are not given names, such as
+// "Mat- och dryckesdagen".
+func SplitCombinedNames(names []Name) []Name {
+	split := make([]Name, 0, len(names))
+	for _, name := range names {
+		if name.TypeOfName == TypeThemeDay {
+			split = append(split, name)
+			continue
+		}
+		parts := splitAny(name.Name, combinedNameSeparators)
+		for _, part := range parts {
+			spellings := splitAny(part, []string{variantSeparator})
+			if len(parts) == 1 && len(spellings) == 1 {
+				split = append(split, name)
+				continue
+			}
+			n := name
+			n.Name = spellings[0]
+			n.Variants = mergeVariants(spellings[1:], name.Variants)
+			if len(parts) > 1 || n.Slug == "" {
+				n.Slug = slugify(n.Name)
+			}
+			split = append(split, n)
+		}
+	}
+	return split
+}
+
+// splitAny splits the string on any of the separators, dropping empty parts.
+// Returns the string as-is if it contains no separators or only empty parts.
+func splitAny(s string, seps []string) []string {
+	parts := []string{s}
+	for _, sep := range seps {
+		var next []string
+		for _, part := range parts {
+			next = append(next, strings.Split(part, sep)...)
+		}
+		parts = next
+	}
+	var nonEmpty []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	if len(nonEmpty) == 0 {
+		return []string{s}
+	}
+	return nonEmpty
+}
+
+// mergeVariants returns the union of the variants, compared using
+// [NormalizeName], in order of appearance.
+func mergeVariants(variants ...[]string) []string {
+	var merged []string
+	seen := map[string]bool{}
+	for _, list := range variants {
+		for _, v := range list {
+			if key := NormalizeName(v); !seen[key] {
+				seen[key] = true
+				merged = append(merged, v)
+			}
+		}
+	}
+	return merged
+}
+
+// slugify returns a URL-friendly version of a name, such as "gosta" for
+// "Gösta", in the same style as the slugs from the website.
+func slugify(name string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	s, _, _ := transform.String(t, strings.ToLower(name))
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "-")
+}